@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gladiusio/gladius-cli/config"
+	"github.com/gladiusio/gladius-cli/utils"
+	"github.com/mgutz/ansi"
+)
+
+// parseAddress - an address given by the user, normalised to its checksummed
+// form. Warns when it has no checksum, as a typo in it can't be caught.
+func (e *env) parseAddress(s, path string) (utils.Address, error) {
+	address, unchecked, err := utils.ParseAddress(s)
+	if err != nil {
+		return "", utils.HandleError(err, err.Error(), path)
+	}
+	if unchecked {
+		e.warnUnchecked(address)
+	}
+	return address, nil
+}
+
+// warnUnchecked - tell the user an all lower case address was not checked
+func (e *env) warnUnchecked(address utils.Address) {
+	warning := fmt.Sprintf("The address has no checksum so typos can't be detected, make sure it is %s", address)
+	fmt.Fprintln(e.stderr, ansi.Color("[WARNING] ", "214+hb")+ansi.Color(warning, "255+hb"))
+}
+
+// validateAddress - survey validator for questions asking for an address
+func validateAddress(val interface{}) error {
+	_, _, err := utils.ParseAddress(val.(string))
+	return err
+}
+
+// parsePool - a pool given by its address or by an alias of it
+func (e *env) parsePool(s, path string) (utils.Address, error) {
+	if address, ok := config.PoolAlias(s); ok {
+		return e.parseAddress(address, path)
+	}
+	if !strings.HasPrefix(s, "0x") {
+		return "", utils.HandleError(errors.New("unknown alias "+s), "There is no pool alias "+s+", see `gladius pools alias list`", path)
+	}
+	return e.parseAddress(s, path)
+}
+
+// validatePool - survey validator for questions asking for a pool
+func validatePool(val interface{}) error {
+	if _, ok := config.PoolAlias(val.(string)); ok {
+		return nil
+	}
+	if s := val.(string); s != "" && !strings.HasPrefix(s, "0x") {
+		return errors.New("There is no pool alias " + s)
+	}
+	return validateAddress(val)
+}
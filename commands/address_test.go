@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gladiusio/gladius-cli/mock"
+	"github.com/gladiusio/gladius-cli/utils"
+)
+
+func TestAddressChecksum(t *testing.T) {
+	state := mock.NewState()
+	state.Locked = false
+	startMock(t, state)
+
+	// no checksum to check, the user is warned
+	output, err := run(t, nil, "", "pool-admin", "applications", "list", "--pool", strings.ToLower(testPool))
+	if err != nil {
+		t.Fatalf("%s\n%s", err, output)
+	}
+	mustContain(t, output, "[WARNING]", "no checksum", testPool)
+
+	output, err = run(t, nil, "", "pool-admin", "applications", "list", "--pool", testPool)
+	if err != nil || strings.Contains(output, "[WARNING]") {
+		t.Errorf("checksummed address: %v\n%s", err, output)
+	}
+
+	_, err = run(t, nil, "", "pool-admin", "applications", "list", "--pool", strings.Replace(testPool, "aA", "Aa", 1))
+	if response, ok := err.(*utils.ErrorResponse); !ok || response.Message() != utils.ErrAddressChecksum.Error() {
+		t.Errorf("bad checksum: %v, want %v", err, utils.ErrAddressChecksum)
+	}
+}
@@ -0,0 +1,428 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gladiusio/gladius-cli/node"
+	"github.com/gladiusio/gladius-cli/utils"
+	log "github.com/sirupsen/logrus"
+	survey "gopkg.in/AlecAivazis/survey.v1"
+)
+
+// applicationQuestion - a question of the application to a pool
+type applicationQuestion struct {
+	*survey.Question
+	Sensitive bool // never saved in the draft, asked again when resuming
+	Country   bool // answered with a country name, sent with its code in <name>Code
+}
+
+// check - an error when the prompt wouldn't have accepted answer, checked
+// like a typed one
+func (q applicationQuestion) check(answer interface{}) error {
+	// what the prompt answers, before the transform
+	var value interface{} = fmt.Sprint(answer)
+	switch prompt := q.Prompt.(type) {
+	case *survey.Select:
+		if !contains(prompt.Options, fmt.Sprint(answer)) {
+			return fmt.Errorf("%q is not one of the choices", answer)
+		}
+	case *survey.MultiSelect:
+		choices := toStrings(answer)
+		if choices == nil && answer != nil {
+			return fmt.Errorf("%v is not a list of choices", answer)
+		}
+		for _, choice := range choices {
+			if !contains(prompt.Options, choice) {
+				return fmt.Errorf("%q is not one of the choices", choice)
+			}
+		}
+		value = choices
+	}
+
+	if q.Validate == nil {
+		return nil
+	}
+	return q.Validate(value)
+}
+
+func contains(options []string, s string) bool {
+	for _, option := range options {
+		if option == s {
+			return true
+		}
+	}
+	return false
+}
+
+// message - what the question asks
+func (q applicationQuestion) message() string {
+	switch prompt := q.Prompt.(type) {
+	case *survey.Input:
+		return prompt.Message
+	case *utils.CountryInput:
+		return prompt.Message
+	case *survey.Select:
+		return prompt.Message
+	case *survey.MultiSelect:
+		return prompt.Message
+	}
+	return q.Name
+}
+
+// poolQuestion - the pool to apply to, asked before anything else as the
+// other questions depend on it
+func poolQuestion() applicationQuestion {
+	return applicationQuestion{Question: &survey.Question{
+		Name:     "pool",
+		Prompt:   &survey.Input{Message: "Pool Address: ", Help: "The address of the pool, or an alias from `gladius pools alias list`"},
+		Validate: validatePool,
+	}}
+}
+
+// builtinQuestions - what the CLI asks when a pool has no form of its own
+func builtinQuestions() []applicationQuestion {
+	return []applicationQuestion{
+		{Question: &survey.Question{
+			Name:      "name",
+			Prompt:    &survey.Input{Message: "What is your name?"},
+			Validate:  survey.Required,
+			Transform: survey.Title,
+		}},
+		{Question: &survey.Question{
+			Name:   "email",
+			Prompt: &survey.Input{Message: "What is your email?"},
+			Validate: func(val interface{}) error {
+				re := regexp.MustCompile(emailPattern) // regex for email
+				if val.(string) == "" {
+					log.WithFields(log.Fields{"file": "application.go", "func": "builtinQuestions"}).Warning("Empty value")
+					return errors.New("This is a required field")
+				} else if !re.MatchString(val.(string)) {
+					log.WithFields(log.Fields{"file": "application.go", "func": "builtinQuestions"}).Warning("Invalid Email")
+					return errors.New("Please enter a valid email address")
+				} else {
+					return nil
+				}
+			},
+		}, Sensitive: true},
+		countryQuestion("location", "What country are you in?", "", true),
+		{Question: &survey.Question{
+			Name:   "estimatedSpeed",
+			Prompt: &survey.Input{Message: "How much bandwidth do you have? (Mbps)"},
+			Validate: func(val interface{}) error {
+				re := regexp.MustCompile("^[0-9]*$") // regex for speed
+				if val.(string) == "" {
+					log.WithFields(log.Fields{"file": "application.go", "func": "builtinQuestions"}).Warning("Empty value")
+					return errors.New("This is a required field")
+				} else if !re.MatchString(val.(string)) {
+					log.WithFields(log.Fields{"file": "application.go", "func": "builtinQuestions"}).Warning("Invalid bandwidth value")
+					return errors.New("Please enter a valid integer")
+				} else {
+					return nil
+				}
+			},
+			Transform: survey.Title,
+		}},
+		{Question: &survey.Question{
+			Name:     "bio",
+			Prompt:   &survey.Input{Message: "Why do you want to join this pool?"},
+			Validate: survey.Required,
+		}},
+	}
+}
+
+// emailPattern - what the built-in question and email fields accept
+const emailPattern = "^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$"
+
+// countryQuestion - a question answered with a country, suggesting the one
+// the system is set up for
+func countryQuestion(name, message, help string, required bool) applicationQuestion {
+	prompt := &utils.CountryInput{Message: message, Help: help}
+	if c, ok := utils.DetectCountry(); ok {
+		prompt.Default = c.Name
+	}
+
+	return applicationQuestion{Question: &survey.Question{
+		Name:   name,
+		Prompt: prompt,
+		Validate: func(val interface{}) error {
+			answer := strings.TrimSpace(val.(string))
+			switch {
+			case answer == "" && required:
+				return errors.New("This is a required field")
+			case answer != "":
+				if _, ok := utils.FindCountry(answer); !ok {
+					return fmt.Errorf("There is no country like %q, try its English name or ISO code", answer)
+				}
+			}
+			return nil
+		},
+	}, Country: true}
+}
+
+// formQuestions - the questions of a form published by a pool
+func formQuestions(fields []node.FormField) []applicationQuestion {
+	var questions []applicationQuestion
+	for _, field := range fields {
+		label := field.Label
+		if label == "" {
+			label = field.Name
+		}
+
+		if field.Type == node.FieldCountry {
+			question := countryQuestion(field.Name, label, field.Help, field.Required)
+			question.Sensitive = field.Sensitive
+			questions = append(questions, question)
+			continue
+		}
+
+		q := &survey.Question{Name: field.Name}
+		switch field.Type {
+		case node.FieldChoice:
+			q.Prompt = &survey.Select{Message: label, Options: field.Choices, Help: field.Help}
+		case node.FieldMultiChoice:
+			q.Prompt = &survey.MultiSelect{Message: label, Options: field.Choices, Help: field.Help}
+			if field.Required {
+				q.Validate = survey.Required
+			}
+		default:
+			q.Prompt = &survey.Input{Message: label, Help: field.Help}
+			q.Validate = fieldValidator(field)
+			if field.Type == node.FieldNumber {
+				q.Transform = toNumber
+			}
+		}
+
+		questions = append(questions, applicationQuestion{Question: q, Sensitive: field.Sensitive})
+	}
+	return questions
+}
+
+// fieldValidator - checks an answer typed for a text, number or email field
+func fieldValidator(field node.FormField) survey.Validator {
+	var pattern *regexp.Regexp
+	if field.Pattern != "" {
+		pattern = regexp.MustCompile(field.Pattern) // checked by node.GetForm
+	}
+
+	return func(val interface{}) error {
+		answer := strings.TrimSpace(val.(string))
+		switch {
+		case answer == "" && field.Required:
+			return errors.New("This is a required field")
+		case answer == "":
+			return nil
+		case field.Type == node.FieldNumber && toNumber(answer) == answer:
+			return errors.New("Please enter a number")
+		case field.Type == node.FieldEmail && !regexp.MustCompile(emailPattern).MatchString(answer):
+			return errors.New("Please enter a valid email address")
+		case pattern != nil && !pattern.MatchString(answer):
+			return fmt.Errorf("Please enter a value matching %s", field.Pattern)
+		}
+		return nil
+	}
+}
+
+// toNumber - a number typed by the user, sent as a JSON number. Returns the
+// answer unchanged when it isn't one.
+func toNumber(ans interface{}) interface{} {
+	s := strings.TrimSpace(fmt.Sprint(ans))
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	return ans
+}
+
+// application - the answers being collected, and the draft keeping the ones
+// that aren't sensitive
+type application struct {
+	questions []applicationQuestion
+	answers   map[string]interface{}
+	draft     *node.Draft
+}
+
+// newApplication - an application continuing draft
+func newApplication(questions []applicationQuestion, draft *node.Draft) *application {
+	app := &application{answers: make(map[string]interface{}), draft: draft}
+	app.add(questions)
+	return app
+}
+
+// add - ask more questions, using the answers already in the draft. Answers
+// the question doesn't accept, e.g. as the pool changed its form since, are
+// asked again.
+func (a *application) add(questions []applicationQuestion) {
+	for _, q := range questions {
+		answer, ok := a.draft.Answers[q.Name]
+		if !ok || q.Sensitive {
+			continue
+		}
+		if err := q.check(answer); err != nil {
+			log.WithFields(log.Fields{"file": "application.go", "func": "add", "question": q.Name}).Info("Asking again, the saved answer is not valid: ", err)
+			continue
+		}
+		a.answers[q.Name] = answer
+	}
+	a.questions = append(a.questions, questions...)
+}
+
+// set - answer a question and save the draft
+func (a *application) set(q applicationQuestion, answer interface{}) {
+	a.answers[q.Name] = answer
+	if q.Sensitive {
+		return
+	}
+
+	a.draft.Answers[q.Name] = answer
+	err := a.draft.Save()
+	if err != nil {
+		log.WithFields(log.Fields{"file": "application.go", "func": "set"}).Warning("Could not save the application draft: ", err)
+	}
+}
+
+// submission - the answers to send, with the countries by their name and
+// ISO code whatever way they were typed or saved in the draft
+func (a *application) submission() map[string]interface{} {
+	answers := make(map[string]interface{}, len(a.answers))
+	for name, answer := range a.answers {
+		answers[name] = answer
+	}
+	for _, q := range a.questions {
+		if !q.Country {
+			continue
+		}
+		if c, ok := utils.FindCountry(fmt.Sprint(a.answers[q.Name])); ok {
+			answers[q.Name] = c.Name
+			answers[q.Name+"Code"] = c.Code
+		}
+	}
+	return answers
+}
+
+// askQuestion - ask q, suggesting the current answer
+func (e *env) askQuestion(app *application, q applicationQuestion) error {
+	if current, ok := app.answers[q.Name]; ok {
+		switch prompt := q.Prompt.(type) {
+		case *survey.Input:
+			prompt.Default = fmt.Sprint(current)
+		case *utils.CountryInput:
+			prompt.Default = fmt.Sprint(current)
+		case *survey.Select:
+			prompt.Default = fmt.Sprint(current)
+		case *survey.MultiSelect:
+			prompt.Default = toStrings(current)
+		}
+	}
+
+	answer := make(map[string]interface{})
+	err := e.prompter.Ask([]*survey.Question{q.Question}, &answer)
+	if err != nil {
+		return err
+	}
+
+	value := answer[q.Name]
+	if q.Name == "pool" {
+		// saved as the checksummed address, not the alias
+		pool, err := e.parsePool(fmt.Sprint(value), "commands.askQuestion")
+		if err != nil {
+			return err
+		}
+		value = pool.String()
+	}
+
+	app.set(q, value)
+	return nil
+}
+
+// toStrings - the choices of a multichoice answer, which are []interface{}
+// once read back from the draft
+func toStrings(answer interface{}) []string {
+	switch answer := answer.(type) {
+	case []string:
+		return answer
+	case []interface{}:
+		choices := make([]string, 0, len(answer))
+		for _, choice := range answer {
+			choices = append(choices, fmt.Sprint(choice))
+		}
+		return choices
+	}
+	return nil
+}
+
+// askApplication - ask every question that has no answer yet
+func (e *env) askApplication(app *application) error {
+	for _, q := range app.questions {
+		if _, ok := app.answers[q.Name]; ok {
+			continue
+		}
+		err := e.askQuestion(app, q)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reviewApplication - show every answer and let the user change them until
+// they submit
+func (e *env) reviewApplication(app *application) error {
+	const submit = "Submit the application"
+
+	for {
+		// the pool can't be changed here, the questions depend on it
+		options := []string{submit}
+		for _, q := range app.questions[1:] {
+			answer := app.answers[q.Name]
+			if choices := toStrings(answer); choices != nil {
+				answer = strings.Join(choices, ", ")
+			}
+			options = append(options, fmt.Sprintf("%s %v", q.message(), answer))
+		}
+
+		var choice string
+		err := e.prompter.AskOne(&survey.Select{
+			Message:  fmt.Sprintf("Review your application to %s, choose an answer to change it:", app.answers["pool"]),
+			Options:  options,
+			PageSize: len(options),
+		}, &choice, nil)
+		if err != nil {
+			return err
+		}
+		if choice == submit {
+			return nil
+		}
+
+		for i, option := range options[1:] {
+			if option == choice {
+				err = e.askQuestion(app, app.questions[i+1])
+				if err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+}
+
+// formFor - the questions of the application to pool, its own form or the
+// built-in questions when it has none
+func (e *env) formFor(pool string) ([]applicationQuestion, error) {
+	fields, err := node.GetForm(e.ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		log.WithFields(log.Fields{"file": "application.go", "func": "formFor"}).Info("No application form, using the built-in questions")
+		return builtinQuestions(), nil
+	}
+
+	log.WithFields(log.Fields{"file": "application.go", "func": "formFor", "fields": len(fields)}).Info("Using the application form of the pool")
+	return formQuestions(fields), nil
+}
@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gladiusio/gladius-cli/config"
+	"github.com/gladiusio/gladius-cli/node"
+	"github.com/gladiusio/gladius-cli/utils"
+)
+
+// answers saved in a draft are only used when the question accepts them,
+// the others are asked again
+func TestApplicationResumeValidates(t *testing.T) {
+	fields := []node.FormField{
+		{Name: "email", Type: node.FieldEmail, Required: true},
+		{Name: "bandwidth", Type: node.FieldNumber, Required: true},
+		{Name: "handle", Pattern: "^@[a-z]+$"},
+		{Name: "tier", Type: node.FieldChoice, Choices: []string{"small", "large"}},
+		{Name: "regions", Type: node.FieldMultiChoice, Choices: []string{"eu", "us"}, Required: true},
+		{Name: "country", Type: node.FieldCountry, Required: true},
+		{Name: "comment"},
+	}
+
+	tests := []struct {
+		name   string
+		draft  map[string]interface{}
+		resume []string // questions answered from the draft
+	}{
+		{
+			name: "valid",
+			draft: map[string]interface{}{
+				"email": "ada@example.com", "bandwidth": 100.0, "handle": "@ada", "tier": "large",
+				"regions": []interface{}{"eu", "us"}, "country": "France", "comment": "",
+			},
+			resume: []string{"email", "bandwidth", "handle", "tier", "regions", "country", "comment"},
+		},
+		{
+			name: "invalid",
+			draft: map[string]interface{}{
+				"email": "ada@", "bandwidth": "fast", "handle": "ada", "tier": "medium",
+				"regions": []interface{}{"eu", "asia"}, "country": "Atlantis", "comment": "hello",
+			},
+			resume: []string{"comment"},
+		},
+		{
+			name: "emptied",
+			draft: map[string]interface{}{
+				"email": "", "bandwidth": "", "regions": []interface{}{}, "country": "",
+			},
+		},
+		{
+			name:  "wrong types",
+			draft: map[string]interface{}{"regions": "eu", "tier": []interface{}{"small"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app := newApplication(formQuestions(fields), &node.Draft{Answers: test.draft})
+
+			if len(app.answers) != len(test.resume) {
+				t.Errorf("resumed %v, want only %v", app.answers, test.resume)
+			}
+			for _, name := range test.resume {
+				if _, ok := app.answers[name]; !ok {
+					t.Errorf("%s is asked again, its answer %v is valid", name, test.draft[name])
+				}
+			}
+		})
+	}
+}
+
+// countries are sent by their name, not the way they were typed or saved
+func TestApplicationCountries(t *testing.T) {
+	config.SetBaseDir(t.TempDir())
+	defer config.SetBaseDir("")
+
+	questions := []applicationQuestion{
+		countryQuestion("location", "Where is your node?", "", true),
+		countryQuestion("birthplace", "Where were you born?", "", false),
+		countryQuestion("billing", "Where do you pay taxes?", "", false),
+		countryQuestion("nationality", "Nationality?", "", false),
+	}
+	app := newApplication(questions, &node.Draft{Answers: map[string]interface{}{"location": "usa"}})
+
+	// Atlantis is asked again
+	var out bytes.Buffer
+	in := "  cote divoire \nU.K.\nAtlantis\n\n"
+	e := newEnv(Options{Out: &out, Err: &out, Prompter: utils.NewLinePrompter(strings.NewReader(in), &out)})
+	err := e.askApplication(app)
+	if err != nil {
+		t.Fatalf("%s\n%s", err, out.String())
+	}
+
+	submission := app.submission()
+	want := map[string]string{
+		"location": "United States", "locationCode": "US",
+		"birthplace": "Côte d'Ivoire", "birthplaceCode": "CI",
+		"billing": "United Kingdom", "billingCode": "GB",
+		"nationality": "",
+	}
+	for name, value := range want {
+		if got, _ := submission[name].(string); got != value {
+			t.Errorf("%s sent as %q, want %q", name, got, value)
+		}
+	}
+	if _, ok := submission["nationalityCode"]; ok {
+		t.Error("a code sent for a country left empty")
+	}
+}
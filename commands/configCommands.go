@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/gladiusio/gladius-cli/config"
+	"github.com/gladiusio/gladius-cli/node"
+	"github.com/gladiusio/gladius-cli/utils"
+	"github.com/mgutz/ansi"
+	"github.com/spf13/cobra"
+)
+
+// configCommand - commands about the CLI's own settings
+func (e *env) configCommand() *cobra.Command {
+	cmdConfig := &cobra.Command{
+		Use:   "config",
+		Short: "See the settings of the CLI",
+		Long:  "See where the CLI keeps its config, logs and state",
+	}
+
+	cmdConfigPaths := &cobra.Command{
+		Use:   "paths",
+		Short: "See where the CLI keeps its files",
+		Long:  "Show the config file, the log file, the lock file, the application draft and the caches the CLI uses",
+		RunE:  e.configPaths,
+	}
+	cmdConfig.AddCommand(cmdConfigPaths)
+
+	return cmdConfig
+}
+
+func (e *env) configPaths(cmd *cobra.Command, args []string) error {
+	paths, err := config.GetPaths()
+	if err != nil {
+		return utils.HandleError(err, err.Error(), "commands.configPaths")
+	}
+
+	configFile := config.ConfigFileUsed()
+	if configFile == "" {
+		configFile = "none, using the defaults"
+	}
+
+	logFile := utils.LogPath
+	if logFile == "" {
+		logFile = filepath.Join(config.GetString("DirLogs"), "log")
+	}
+
+	lockFile, _ := utils.LockPath()
+	versionCache, _ := node.VersionCachePath()
+	draft, _ := node.DraftPath()
+
+	fmt.Fprintln(e.stdout, ansi.Color("CONFIG DIR:", "83+hb"), ansi.Color(paths.Config, "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("CONFIG FILE:", "83+hb"), ansi.Color(configFile, "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("STATE DIR:", "83+hb"), ansi.Color(paths.State, "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("LOG FILE:", "83+hb"), ansi.Color(logFile, "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("LOCK FILE:", "83+hb"), ansi.Color(lockFile, "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("VERSION CACHE:", "83+hb"), ansi.Color(versionCache, "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("APPLICATION DRAFT:", "83+hb"), ansi.Color(draft, "255+hb"))
+
+	return nil
+}
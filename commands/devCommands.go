@@ -0,0 +1,175 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gladiusio/gladius-cli/config"
+	"github.com/gladiusio/gladius-cli/mock"
+	"github.com/gladiusio/gladius-cli/utils"
+	"github.com/mgutz/ansi"
+	"github.com/spf13/cobra"
+)
+
+// mockOptions - flags of dev mock
+type mockOptions struct {
+	unlocked     bool
+	noAccount    bool
+	passphrase   string
+	offline      []string
+	applications []string
+	forms        []string
+	received     []string
+	versions     []string
+	balances     []string
+	blockTime    time.Duration
+	controlPort  int
+}
+
+// devCommand - tools for working on the CLI
+func (e *env) devCommand() *cobra.Command {
+	var opts mockOptions
+
+	cmdDev := &cobra.Command{
+		Use:   "dev",
+		Short: "Tools for working on the CLI",
+		Long:  "Tools for developing and testing the CLI without a real Gladius node",
+	}
+
+	cmdDevMock := &cobra.Command{
+		Use:   "mock",
+		Short: "Run fake Gladius modules",
+		Long: "Run a fake Guardian, EdgeD and Network Gateway on the configured ports so every command can be tried without a real node. " +
+			"The state of the fake modules can be changed while they run with GET/PUT on http://localhost:<control-port>/state",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return e.devMock(opts)
+		},
+	}
+	cmdDev.AddCommand(cmdDevMock)
+
+	cmdDevMock.Flags().BoolVar(&opts.unlocked, "unlocked", false, "start with the wallet unlocked")
+	cmdDevMock.Flags().BoolVar(&opts.noAccount, "no-account", false, "start without a wallet")
+	cmdDevMock.Flags().StringVar(&opts.passphrase, "passphrase", "password", "passphrase of the fake wallet")
+	cmdDevMock.Flags().StringSliceVar(&opts.offline, "offline", nil, "modules to keep offline (guardian, edged, network-gateway)")
+	cmdDevMock.Flags().StringSliceVar(&opts.applications, "application", nil, "existing applications as pool=pending|approved|rejected")
+	cmdDevMock.Flags().StringSliceVar(&opts.forms, "form", nil, "application forms of pools as pool=file.json, the file holding the list of fields")
+	cmdDevMock.Flags().StringSliceVar(&opts.received, "pool-applications", nil, "applications received by pools the account runs as pool=count, from made up nodes")
+	cmdDevMock.Flags().StringSliceVar(&opts.versions, "module-version", nil, "versions reported by the modules as module=version")
+	cmdDevMock.Flags().StringSliceVar(&opts.balances, "balance", nil, "balances of the accounts in the smallest unit as symbol=amount (e.g. eth=1500000000000000000)")
+	cmdDevMock.Flags().DurationVar(&opts.blockTime, "block-time", 3*time.Second, "mine a block containing the pending transactions this often")
+	cmdDevMock.Flags().IntVar(&opts.controlPort, "control-port", 7790, "port of the control API")
+
+	return cmdDev
+}
+
+func (e *env) devMock(opts mockOptions) error {
+	state := mock.NewState()
+	state.Locked = !opts.unlocked
+	state.Passphrase = opts.passphrase
+	if opts.noAccount {
+		state.Account = ""
+	}
+
+	for _, module := range opts.offline {
+		state.Offline[module] = true
+	}
+
+	for _, application := range opts.applications {
+		pool, status, err := splitPair(application)
+		if err != nil {
+			return err
+		}
+		state.SetApplication(pool, status)
+	}
+
+	for _, form := range opts.forms {
+		pool, file, err := splitPair(form)
+		if err != nil {
+			return err
+		}
+		fields, err := ioutil.ReadFile(file)
+		if err != nil {
+			return utils.HandleError(err, "Could not read the form "+file, "commands.devMock")
+		}
+		if !json.Valid(fields) {
+			return utils.HandleError(errors.New("invalid json in "+file), "The form "+file+" is not valid JSON", "commands.devMock")
+		}
+		state.Forms[strings.ToLower(pool)] = fields
+	}
+
+	for _, received := range opts.received {
+		pool, count, err := splitPair(received)
+		if err != nil {
+			return err
+		}
+		n, err := strconv.Atoi(count)
+		if err != nil || n < 0 {
+			return fmt.Errorf("expected pool=count, got %q", received)
+		}
+		state.AddReceived(pool, n)
+	}
+
+	for _, version := range opts.versions {
+		module, v, err := splitPair(version)
+		if err != nil {
+			return err
+		}
+		state.Versions[module] = v
+	}
+
+	for _, balance := range opts.balances {
+		symbol, amount, err := splitPair(balance)
+		if err != nil {
+			return err
+		}
+		state.Balances[strings.ToLower(symbol)] = amount
+	}
+
+	ports := map[string]int{"control": opts.controlPort}
+	for _, module := range config.Modules {
+		port, _ := config.ModulePort(module.Name)
+		ports[module.Name] = port
+	}
+
+	daemon := mock.New(state)
+	daemon.BlockTime = opts.blockTime
+	err := daemon.Start(ports)
+	if err != nil {
+		return utils.HandleError(err, "Could not start the fake modules", "commands.devMock")
+	}
+	defer daemon.Close()
+
+	for _, module := range append(mock.Modules, "control") {
+		status := fmt.Sprintf("http://localhost:%d", daemon.Ports()[module])
+		if state.Offline[module] {
+			status = "offline"
+		}
+		fmt.Fprintln(e.stdout, ansi.Color(module+":", "83+hb"), ansi.Color(status, "255+hb"))
+	}
+	fmt.Fprintln(e.stdout, ansi.Color("\nFake modules running, press Ctrl-C to stop", "255+hb"))
+
+	stopWatching := e.watchConfig(func(previous, next config.Config) error {
+		if previous.Ports != next.Ports {
+			return errors.New("the fake modules keep their ports, restart `gladius dev mock` to move them")
+		}
+		return nil
+	})
+	defer stopWatching()
+
+	<-e.ctx.Done()
+	return nil
+}
+
+// splitPair - "key=value"
+func splitPair(s string) (string, string, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", fmt.Errorf("expected key=value, got %q", s)
+	}
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,277 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gladiusio/gladius-cli/config"
+	"github.com/gladiusio/gladius-cli/mock"
+	"github.com/gladiusio/gladius-cli/utils"
+	"github.com/spf13/viper"
+)
+
+const (
+	testPool = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	testNode = "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"
+)
+
+// startMock - fake modules on free ports, which the commands are pointed at,
+// mining a block every 10ms
+func startMock(t *testing.T, state *mock.State) *mock.Daemon {
+	return startMockMining(t, state, 10*time.Millisecond)
+}
+
+// startMockMining - startMock mining a block every blockTime, never when 0
+func startMockMining(t *testing.T, state *mock.State, blockTime time.Duration) *mock.Daemon {
+	daemon := mock.New(state)
+	daemon.BlockTime = blockTime
+
+	ports := map[string]int{"control": 0}
+	for _, module := range mock.Modules {
+		ports[module] = 0
+	}
+	err := daemon.Start(ports)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := daemon.Ports()
+	for _, module := range config.Modules {
+		viper.Set(module.PortKey, started[module.Name])
+	}
+	t.Cleanup(func() {
+		daemon.Close()
+		for _, module := range config.Modules {
+			viper.Set(module.PortKey, nil)
+		}
+	})
+
+	return daemon
+}
+
+// getState - the state of the fake modules, read with the control API
+func getState(t *testing.T, daemon *mock.Daemon) *mock.State {
+	t.Helper()
+	res, err := http.Get(fmt.Sprintf("http://localhost:%d/state", daemon.Ports()["control"]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	state := &mock.State{}
+	err = json.NewDecoder(res.Body).Decode(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
+
+// changeState - change the state of the fake modules with the control API
+func changeState(t *testing.T, daemon *mock.Daemon, change func(s *mock.State)) {
+	t.Helper()
+	state := getState(t, daemon)
+	change(state)
+
+	b, err := json.Marshal(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest("PUT", fmt.Sprintf("http://localhost:%d/state", daemon.Ports()["control"]), bytes.NewReader(b))
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		t.Fatalf("PUT /state answered %d", res.StatusCode)
+	}
+}
+
+func mustContain(t *testing.T, output string, parts ...string) {
+	t.Helper()
+	for _, part := range parts {
+		if !strings.Contains(output, part) {
+			t.Errorf("output doesn't contain %q:\n%s", part, output)
+		}
+	}
+}
+
+func TestMockStatus(t *testing.T) {
+	daemon := startMock(t, nil)
+
+	output, err := run(t, nil, "", "status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(output, "NOT ONLINE") {
+		t.Errorf("a module is offline:\n%s", output)
+	}
+
+	// a module stopped through the control API
+	changeState(t, daemon, func(s *mock.State) { s.Offline["edged"] = true })
+
+	output, err = run(t, nil, "", "status", "--no-retry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustContain(t, output, "EDGE DAEMON:\t NOT ONLINE", "NETWORK GATEWAY: ONLINE")
+}
+
+func TestMockApply(t *testing.T) {
+	daemon := startMock(t, nil)
+
+	// name, email, country, bandwidth, bio and then the passphrase the
+	// locked wallet asks for
+	in := "ada lovelace\nada@example.com\nfrance\n100\nI have a fast connection\npassword\n"
+	output, err := run(t, nil, in, "apply", "--pool", testPool)
+	if err != nil {
+		t.Fatalf("%s\n%s", err, output)
+	}
+	mustContain(t, output, "Your application has been sent!")
+
+	application, ok := getState(t, daemon).Applications[strings.ToLower(testPool)]
+	if !ok {
+		t.Fatal("the mock didn't receive the application")
+	}
+	profile := application.Profile
+	if profile["name"] != "Ada Lovelace" || profile["location"] != "France" || profile["locationCode"] != "FR" || profile["estimatedSpeed"] != "100" {
+		t.Errorf("profile = %v", profile)
+	}
+	if !application.Pending {
+		t.Error("the application isn't pending")
+	}
+}
+
+func TestMockBalance(t *testing.T) {
+	startMock(t, nil)
+
+	output, err := run(t, nil, "", "balance")
+	if err != nil {
+		t.Fatalf("%s\n%s", err, output)
+	}
+	mustContain(t, output, "1.5", "2500")
+}
+
+func TestMockTransfer(t *testing.T) {
+	daemon := startMock(t, nil)
+	changeState(t, daemon, func(s *mock.State) { s.Locked = false })
+
+	output, err := run(t, nil, "0.5 ETH\n", "wallet", "transfer", "0.5", "ETH", testNode)
+	if err != nil {
+		t.Fatalf("%s\n%s", err, output)
+	}
+	mustContain(t, output, "AMOUNT: 0.5 ETH", "TO: "+testNode)
+
+	state := getState(t, daemon)
+	if len(state.Transactions) != 1 {
+		t.Fatalf("%d transactions sent, want 1", len(state.Transactions))
+	}
+	for hash, tx := range state.Transactions {
+		if tx.Block == 0 {
+			t.Errorf("transaction %s wasn't waited for", hash)
+		}
+	}
+
+	// a mismatching confirmation sends nothing
+	_, err = run(t, nil, "5 ETH\n", "wallet", "transfer", "0.5", "ETH", testNode)
+	if err == nil {
+		t.Error("no error when the confirmation doesn't match")
+	}
+	if n := len(getState(t, daemon).Transactions); n != 1 {
+		t.Errorf("%d transactions after a cancelled transfer, want 1", n)
+	}
+}
+
+// other commands can change the node while transfer waits for its
+// transaction
+func TestMockTransferReleasesLock(t *testing.T) {
+	state := mock.NewState()
+	state.Locked = false
+	daemon := startMockMining(t, state, 0)
+
+	done := make(chan error)
+	go func() {
+		_, err := run(t, nil, "0.5 ETH\n", "wallet", "transfer", "0.5", "ETH", testNode)
+		done <- err
+	}()
+
+	for deadline := time.Now().Add(5 * time.Second); len(getState(t, daemon).Transactions) == 0; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("the transaction was never sent")
+		}
+	}
+
+	// the lock is released right after sending, give it a moment
+	var release func()
+	var err error
+	for attempt := 0; attempt < 50; attempt++ {
+		release, err = utils.AcquireLock(context.Background(), "stop", false)
+		if err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal("transfer holds the lock while waiting for the transaction: ", err)
+	}
+	release()
+
+	select {
+	case err := <-done:
+		t.Fatal("transfer returned before the transaction was mined: ", err)
+	default:
+	}
+
+	daemon.State.Mine()
+	err = <-done
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMockPoolAdmin(t *testing.T) {
+	state := mock.NewState()
+	state.Locked = false
+	state.AddReceived(testPool, 3)
+	daemon := startMock(t, state)
+
+	output, err := run(t, nil, "", "pool-admin", "applications", "list", "--pool", testPool, "--json")
+	if err != nil {
+		t.Fatalf("%s\n%s", err, output)
+	}
+	var listed []struct {
+		Node   string `json:"node"`
+		Status string `json:"status"`
+	}
+	err = json.Unmarshal([]byte(output), &listed)
+	if err != nil {
+		t.Fatalf("%s\n%s", err, output)
+	}
+	if len(listed) != 3 {
+		t.Fatalf("%d applications listed, want 3", len(listed))
+	}
+
+	node := listed[0].Node
+	output, err = run(t, nil, "", "pool-admin", "applications", "approve", "--pool", testPool, node, "--message", "Welcome!")
+	if err != nil {
+		t.Fatalf("%s\n%s", err, output)
+	}
+
+	application := getState(t, daemon).Received[strings.ToLower(testPool)][strings.ToLower(node)]
+	if application == nil || application.Pending || !application.Approved || application.Message != "Welcome!" {
+		t.Errorf("application after approving = %+v", application)
+	}
+
+	// the gateway going away halfway is reported, not ignored
+	changeState(t, daemon, func(s *mock.State) { s.Offline["network-gateway"] = true })
+	_, err = run(t, nil, "", "pool-admin", "applications", "reject", "--pool", testPool, listed[1].Node, "--no-retry")
+	if err == nil {
+		t.Error("no error with the Network Gateway offline")
+	}
+}
@@ -3,8 +3,11 @@ package commands
 import (
 	"errors"
 	"fmt"
-	"regexp"
+	"os"
+	"strconv"
+	"time"
 
+	"github.com/gladiusio/gladius-cli/config"
 	"github.com/gladiusio/gladius-cli/keystore"
 	"github.com/gladiusio/gladius-cli/node"
 	"github.com/gladiusio/gladius-cli/utils"
@@ -12,393 +15,432 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	survey "gopkg.in/AlecAivazis/survey.v1"
-	surveyCore "gopkg.in/AlecAivazis/survey.v1/core"
-	"gopkg.in/AlecAivazis/survey.v1/terminal"
 )
 
-var cmdApply = &cobra.Command{
-	Use:   "apply",
-	Short: "Apply to a Gladius Pool",
-	Long:  "Send your Node's data (encrypted) to the pool owner as an application",
-	Run:   applyToPool,
-}
+// nodeCommands - the commands for running a node
+func (e *env) nodeCommands() []*cobra.Command {
+	var versionCheck bool
+	var applyPool, checkPool string
+	var applyResume, applyReview bool
+
+	cmdApply := &cobra.Command{
+		Annotations: requires("/api/keystore/account", "/api/keystore/account/create", "/api/node/applications/<pool>/new"),
+		Use:         "apply",
+		Short:       "Apply to a Gladius Pool",
+		Long:        "Send your Node's data (encrypted) to the pool owner as an application. Your answers are saved as you go, continue an interrupted application with --resume",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return e.applyToPool(applyPool, applyResume, applyReview)
+		},
+	}
+	cmdApply.Flags().StringVar(&applyPool, "pool", "", "address or alias of the pool, instead of asking for it")
+	cmdApply.Flags().BoolVar(&applyResume, "resume", false, "continue the application that was interrupted")
+	cmdApply.Flags().BoolVar(&applyReview, "review", false, "show all the answers and change them before sending")
+
+	cmdCheck := &cobra.Command{
+		Annotations: requires("/api/node/applications/<pool>/view"),
+		Use:         "check",
+		Short:       "Check status of your submitted pool application",
+		Long:        "Check status of your submitted pool application. Asks for the pool, suggesting the last pool you applied to",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return e.checkPoolApp(checkPool)
+		},
+	}
+	cmdCheck.Flags().StringVar(&checkPool, "pool", "", "address or alias of the pool, instead of asking for it")
 
-var cmdCheck = &cobra.Command{
-	Use:   "check",
-	Short: "Check status of your submitted pool application",
-	Long:  "Check status of your submitted pool application",
-	Run:   checkPoolApp,
-}
+	cmdStatus := &cobra.Command{
+		Use:   "status",
+		Short: "See the status of your node",
+		Long:  "See the status of each module",
+		RunE:  e.status,
+	}
 
-var cmdStatus = &cobra.Command{
-	Use:   "status",
-	Short: "See the status of your node",
-	Long:  "See the status of each module",
-	Run:   status,
-}
+	cmdProfile := &cobra.Command{
+		Annotations: requires("/api/keystore/account"),
+		Use:         "profile",
+		Short:       "See your profile information",
+		Long:        "Display current users profile information",
+		RunE:        e.profile,
+	}
 
-var cmdProfile = &cobra.Command{
-	Use:   "profile",
-	Short: "See your profile information",
-	Long:  "Display current users profile information",
-	Run:   profile,
-}
+	cmdVersion := &cobra.Command{
+		Use:   "version",
+		Short: "See the version of the Gladius Network",
+		Long:  "See versions of the Gladius Network modules",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if versionCheck {
+				return e.checkCompatibility()
+			}
+			return e.version(cmd, args)
+		},
+	}
 
-var cmdVersion = &cobra.Command{
-	Use:   "version",
-	Short: "See the version of the Gladius Network",
-	Long:  "See versions of the Gladius Network modules",
-	Run:   version,
-}
+	cmdStart := &cobra.Command{
+		Annotations: requires("/service/set_timeout", "/service/set_state/all"),
+		Use:         "start",
+		Short:       "Start the gladius modules",
+		Long:        "Start the EdgeD and Network Gateway",
+		RunE:        e.start,
+	}
 
-var cmdStart = &cobra.Command{
-	Use:   "start",
-	Short: "Start the gladius modules",
-	Long:  "Start the EdgeD and Network Gateway",
-	Run:   start,
-}
+	cmdStop := &cobra.Command{
+		Annotations: requires("/service/set_state/all"),
+		Use:         "stop",
+		Short:       "Stop the gladius modules",
+		Long:        "Stop the EdgeD and Network Gateway",
+		RunE:        e.stop,
+	}
 
-var cmdStop = &cobra.Command{
-	Use:   "stop",
-	Short: "Stop the gladius modules",
-	Long:  "Stop the EdgeD and Network Gateway",
-	Run:   stop,
-}
+	cmdUnlock := &cobra.Command{
+		Annotations: requires("/api/keystore/account/open"),
+		Use:         "unlock",
+		Short:       "Unlock your wallet",
+		Long:        "Unlock the gladius wallet in the Network Gateway",
+		RunE:        e.unlock,
+	}
 
-var cmdUnlock = &cobra.Command{
-	Use:   "unlock",
-	Short: "Unlock your wallet",
-	Long:  "Unlock the gladius wallet in the Network Gateway",
-	Run:   unlock,
-}
+	cmdUpdate := &cobra.Command{
+		Use:   "update",
+		Short: "Check for updates for your node",
+		Long:  "Check for updates your node modules, always fetching the newest version list",
+		RunE:  e.update,
+	}
 
-var cmdUpdate = &cobra.Command{
-	Use:   "update",
-	Short: "Check for updates for your node",
-	Long:  "Check for updates your node modules",
-	Run:   update,
+	// register all flags
+	cmdVersion.Flags().BoolVar(&versionCheck, "check", false, "check the running modules against the versions this CLI supports")
+
+	// commands changing the node run one at a time
+	e.locked(cmdApply)
+	e.locked(cmdStart)
+	e.locked(cmdStop)
+	e.locked(cmdUnlock)
+
+	return []*cobra.Command{cmdApply, cmdCheck, cmdStatus, cmdProfile, cmdVersion, cmdStart, cmdStop, cmdUnlock, cmdUpdate}
 }
 
 // collect user info, send application to the server
-func applyToPool(cmd *cobra.Command, args []string) {
-	utils.SetLogLevel(utils.LogLevel)
-	defer utils.LogFile.Close()
+func (e *env) applyToPool(pool string, resume, review bool) error {
+	saved, err := node.LoadDraft()
+	if err != nil {
+		log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "applyToPool"}).Warning("Could not read the application draft: ", err)
+	}
+
+	draft := &node.Draft{Answers: make(map[string]interface{})}
+	switch {
+	case resume && saved == nil:
+		return utils.HandleError(errors.New("no draft"), "There is no unfinished application to resume, start one with `gladius apply`", "commands.applyToPool")
+	case resume:
+		draft = saved
+	case saved != nil:
+		warning := fmt.Sprintf("Starting a new application, the unfinished one from %s is replaced. Use `gladius apply --resume` to continue it instead", saved.Updated.Format("Jan 2 15:04"))
+		fmt.Fprintln(e.stderr, ansi.Color("[WARNING] ", "214+hb")+ansi.Color(warning, "255+hb"))
+	}
+
+	app := newApplication([]applicationQuestion{poolQuestion()}, draft)
+
+	// a pool given with --pool is checked before asking anything
+	if pool != "" {
+		poolAddress, err := e.parsePool(pool, "commands.applyToPool")
+		if err != nil {
+			return err
+		}
+		app.set(app.questions[0], poolAddress.String())
+	}
 
 	// make sure they have a account, if they dont, make one
 	log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "applyToPool"}).Info("Checking for account")
-	account, _ := keystore.EnsureAccount()
+	account, _ := keystore.EnsureAccount(e.ctx)
 	if !account {
 		log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "createNewNode"}).Warning("No account found")
-		res, err := keystore.CreateAccount()
+		res, err := keystore.CreateAccount(e.ctx)
 		if err != nil {
-			utils.PrintError(err)
+			return err
 		}
 		log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "createNewNode"}).Info(res)
-		fmt.Println()
-		terminal.Println(ansi.Color("Remember your passphrase! It's how you unlock your wallet!", "83+hb"))
-		fmt.Println()
+		fmt.Fprintln(e.stdout)
+		fmt.Fprintln(e.stdout, ansi.Color("Remember your passphrase! It's how you unlock your wallet!", "83+hb"))
+		fmt.Fprintln(e.stdout)
 	}
 	log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "applyToPool"}).Info("Account found")
 
-	// create the user questions
-	var qs = []*survey.Question{
-		{
-			Name:   "pool",
-			Prompt: &survey.Input{Message: "Pool Address: "},
-			Validate: func(val interface{}) error {
-				re := regexp.MustCompile("^0x[a-fA-F0-9]{40}$") // regex for email
-				if val.(string) == "" {
-					log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "applyToPool"}).Warning("Empty value")
-					return errors.New("This is a required field")
-				} else if !re.MatchString(val.(string)) {
-					log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "applyToPool"}).Warning("Invalid email")
-					return errors.New("Please enter a valid ethereum address")
-				} else {
-					return nil
-				}
-			},
-		},
-		{
-			Name:      "name",
-			Prompt:    &survey.Input{Message: "What is your name?"},
-			Validate:  survey.Required,
-			Transform: survey.Title,
-		},
-		{
-			Name:   "email",
-			Prompt: &survey.Input{Message: "What is your email?"},
-			Validate: func(val interface{}) error {
-				re := regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$") // regex for email
-				if val.(string) == "" {
-					log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "createNewNode"}).Warning("Empty value")
-					return errors.New("This is a required field")
-				} else if !re.MatchString(val.(string)) {
-					log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "createNewNode"}).Warning("Invalid Email")
-					return errors.New("Please enter a valid email address")
-				} else {
-					return nil
-				}
-			},
-		},
-		{
-			Name:      "location",
-			Prompt:    &survey.Input{Message: "What country are you in?"},
-			Validate:  survey.Required,
-			Transform: survey.Title,
-		},
-		{
-			Name:   "estimatedSpeed",
-			Prompt: &survey.Input{Message: "How much bandwidth do you have? (Mbps)"},
-			Validate: func(val interface{}) error {
-				re := regexp.MustCompile("^[0-9]*$") // regex for speed
-				if val.(string) == "" {
-					log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "applyToPool"}).Warning("Empty value")
-					return errors.New("This is a required field")
-				} else if !re.MatchString(val.(string)) {
-					log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "applyToPool"}).Warning("Invalid bandwidth value")
-					return errors.New("Please enter a valid integer")
-				} else {
-					return nil
-				}
-			},
-			Transform: survey.Title,
-		},
-		{
-			Name:     "bio",
-			Prompt:   &survey.Input{Message: "Why do you want to join this pool?"},
-			Validate: survey.Required,
-		},
+	log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "applyToPool"}).Info("Collecting application info")
+	// perform the questions, the draft is saved after every answer
+	err = e.askApplication(app)
+	if err != nil {
+		return utils.HandleError(err, "Application not sent, your answers are saved. Continue with `gladius apply --resume`", "commands.applyToPool")
 	}
 
-	// the answers will be written to this struct
-	answers := make(map[string]interface{})
+	// the rest of the questions come from the pool
+	questions, err := e.formFor(app.answers["pool"].(string))
+	if err != nil {
+		return err
+	}
+	app.add(questions)
 
-	log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "applyToPool"}).Info("Collecting application info")
-	// perform the questions
-	err := survey.Ask(qs, &answers)
+	err = e.askApplication(app)
+	if err == nil && review {
+		err = e.reviewApplication(app)
+	}
 	if err != nil {
-		utils.PrintError(err)
+		return utils.HandleError(err, "Application not sent, your answers are saved. Continue with `gladius apply --resume`", "commands.applyToPool")
 	}
 
 	// apply to the application server
 	log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "applyToPool"}).Info("Sending application to server")
-	_, err = node.ApplyToPool(answers["pool"].(string), answers)
+	poolAddress := app.answers["pool"].(string)
+	tx, err := node.ApplyToPool(e.ctx, poolAddress, app.submission())
 	if err != nil {
-		utils.PrintError(err)
-	} else {
-		println()
-		terminal.Println(ansi.Color("Your application has been sent! Use", "255+hb"), ansi.Color("gladius check", "83+hb"),
-			ansi.Color("to check on the status of your application!", "255+hb"))
+		fmt.Fprintln(e.stderr, ansi.Color("Your answers are saved, send them again with", "255+hb"), ansi.Color("gladius apply --resume", "83+hb"))
+		return err
 	}
+	fmt.Fprintln(e.stdout)
+	fmt.Fprintln(e.stdout, ansi.Color("Your application has been sent! Use", "255+hb"), ansi.Color("gladius check", "83+hb"),
+		ansi.Color("to check on the status of your application!", "255+hb"))
+	e.printTx(tx)
 	log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "applyToPool"}).Info("Application sent!")
 
-	checkUpdate()
+	err = node.RemoveDraft()
+	if err != nil {
+		log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "applyToPool"}).Warning("Could not remove the application draft: ", err)
+	}
+
+	// check asks about this pool by default
+	err = config.SetLastPool(poolAddress)
+	if err != nil {
+		log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "applyToPool"}).Warning("Could not remember the pool: ", err)
+	}
+
+	e.checkUpdate()
+	return nil
 }
 
 // unlock your wallet manually
-func unlock(cmd *cobra.Command, args []string) {
-	utils.OpenAccount()
+func (e *env) unlock(cmd *cobra.Command, args []string) error {
+	utils.OpenAccount(e.ctx)
 
-	checkUpdate()
+	e.checkUpdate()
+	return nil
 }
 
 // check the application of the node
-func checkPoolApp(cmd *cobra.Command, args []string) {
-	utils.SetLogLevel(utils.LogLevel)
-	defer utils.LogFile.Close()
-
-	// build question
-	var qs = []*survey.Question{
-		{
-			Name:   "pool",
-			Prompt: &survey.Input{Message: "Pool Address: "},
-			Validate: func(val interface{}) error {
-				re := regexp.MustCompile("^0x[a-fA-F0-9]{40}$") // regex for email
-				if val.(string) == "" {
-					log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "checkPoolApp"}).Warning("Empty value")
-					return errors.New("This is a required field")
-				} else if !re.MatchString(val.(string)) {
-					log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "checkPoolApp"}).Warning("Invalid ETH address")
-					return errors.New("Please enter a valid ethereum address")
-				} else {
-					return nil
-				}
+func (e *env) checkPoolApp(pool string) error {
+	if pool == "" {
+		// build question, suggesting the pool applied to last
+		var qs = []*survey.Question{
+			{
+				Name:     "pool",
+				Prompt:   &survey.Input{Message: "Pool Address: ", Default: config.LastPool(), Help: "The address of the pool, or an alias from `gladius pools alias list`"},
+				Validate: validatePool,
 			},
-		},
-	}
+		}
 
-	// the answers will be written to this struct
-	answers := make(map[string]interface{})
+		// the answers will be written to this struct
+		answers := make(map[string]interface{})
 
-	log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "checkPoolApp"}).Info("Collecting pool address")
-	// perform the questions
-	err := survey.Ask(qs, &answers)
-	if err != nil {
-		utils.PrintError(err)
+		log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "checkPoolApp"}).Info("Collecting pool address")
+		// perform the questions
+		err := e.prompter.Ask(qs, &answers)
+		if err != nil {
+			return err
+		}
+		pool = answers["pool"].(string)
 	}
 
-	poolAddy := answers["pool"]
+	poolAddy, err := e.parsePool(pool, "commands.checkPoolApp")
+	if err != nil {
+		return err
+	}
 
 	log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "checkPoolApp"}).Info("Checking application")
 	// check application status
-	status, err := node.CheckPoolApplication(poolAddy.(string))
+	status, err := node.CheckPoolApplication(e.ctx, poolAddy.String())
 	if err != nil {
-		utils.PrintError(err)
+		return err
 	}
 	log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "checkPoolApp"}).Info("Application checked")
 
-	fmt.Println()
-	terminal.Println(ansi.Color("Pool: "+poolAddy.(string)+"\t Status: "+status, "255+hb"))
-	terminal.Println(ansi.Color("\nOnce your application is approved you will automatically become an edge node!", "255+hb"))
+	fmt.Fprintln(e.stdout)
+	fmt.Fprintln(e.stdout, ansi.Color("Pool: "+poolAddy.String()+"\t Status: "+status, "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("\nOnce your application is approved you will automatically become an edge node!", "255+hb"))
 
-	checkUpdate()
+	e.checkUpdate()
+	return nil
 }
 
 // get a users profile
-func profile(cmd *cobra.Command, args []string) {
-	utils.SetLogLevel(utils.LogLevel)
-	defer utils.LogFile.Close()
-
-	account, err := keystore.GetAccounts()
+func (e *env) profile(cmd *cobra.Command, args []string) error {
+	account, err := keystore.GetAccounts(e.ctx)
 	if err != nil {
-		utils.PrintError(err)
+		return err
 	}
 
-	fmt.Println()
-	terminal.Println(ansi.Color("Account Address:", "83+hb"), ansi.Color(account, "255+hb"))
+	fmt.Fprintln(e.stdout)
+	fmt.Fprintln(e.stdout, ansi.Color("Account Address:", "83+hb"), ansi.Color(account, "255+hb"))
 
-	checkUpdate()
+	e.checkUpdate()
+	return nil
 }
 
 // versions of the modules
-func version(cmd *cobra.Command, args []string) {
-	cli := "0.8.1"
+func (e *env) version(cmd *cobra.Command, args []string) error {
+	cli := utils.CLIVersion
 	offline := "NOT ONLINE"
 
-	guardian, err := node.GetVersion("guardian")
-	if err != nil {
-		guardian = offline
-	}
-	edged, err := node.GetVersion("edged")
-	if err != nil {
-		edged = offline
-	}
-	networkGateway, err := node.GetVersion("network-gateway")
-	if err != nil {
-		networkGateway = offline
+	versions := make(map[string]string)
+	for _, module := range node.ProbeModules(e.ctx) {
+		versions[module.Module.Name] = module.Version
+		if !module.Online {
+			versions[module.Module.Name] = offline
+		}
 	}
 
-	terminal.Println(ansi.Color("CLI:", "83+hb"), ansi.Color(cli, "255+hb"))
-	terminal.Println(ansi.Color("EDGED:", "83+hb"), ansi.Color(edged, "255+hb"))
-	terminal.Println(ansi.Color("NETWORKD:", "83+hb"), ansi.Color(networkGateway, "255+hb"))
-	terminal.Println(ansi.Color("GUARDIAN:", "83+hb"), ansi.Color(guardian, "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("CLI:", "83+hb"), ansi.Color(cli, "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("COMMIT:", "83+hb"), ansi.Color(utils.GitCommit, "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("BUILT:", "83+hb"), ansi.Color(utils.BuildDate, "255+hb"), ansi.Color(utils.GoVersion(), "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("EDGED:", "83+hb"), ansi.Color(versions["edged"], "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("NETWORKD:", "83+hb"), ansi.Color(versions["network-gateway"], "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("GUARDIAN:", "83+hb"), ansi.Color(versions["guardian"], "255+hb"))
 
-	checkUpdate()
+	e.checkUpdate()
+	return nil
 }
 
-func start(cmd *cobra.Command, args []string) {
-	utils.SetLogLevel(utils.LogLevel)
-	defer utils.LogFile.Close()
+// check the running modules against the versions this CLI supports
+func (e *env) checkCompatibility() error {
+	compatible := true
+	for _, report := range node.CheckCompatibility(e.ctx) {
+		supported := ">= " + report.Supported.MinVersion
+		if report.Supported.MaxVersion != "" {
+			supported += ", < " + report.Supported.MaxVersion
+		}
 
-	status, err := node.Start()
-	if err != nil {
-		utils.PrintError(err)
-	} else {
-		terminal.Println(ansi.Color("Network Gateway:", "83+hb"), ansi.Color(status, "255+hb"))
-		terminal.Println(ansi.Color("Edge Daemon:", "83+hb"), ansi.Color(status, "255+hb"))
+		result := ansi.Color("OK", "83+hb")
+		if !report.OK {
+			result = ansi.Color(report.Problem, "196+hb")
+		}
+		if report.Status.Online && !report.OK {
+			compatible = false
+		}
+
+		version := report.Status.Version
+		if !report.Status.Online {
+			version = "NOT ONLINE"
+		}
+
+		fmt.Fprintln(e.stdout, ansi.Color(report.Status.Module.Name+":", "83+hb"), ansi.Color(version, "255+hb"),
+			ansi.Color("(supported "+supported+")", "255+hb"), result)
 	}
 
-	checkUpdate()
+	if !compatible {
+		return errors.New("One or more of your modules is not supported by this version of the CLI")
+	}
+	return nil
 }
 
-func stop(cmd *cobra.Command, args []string) {
-	utils.SetLogLevel(utils.LogLevel)
-	defer utils.LogFile.Close()
+func (e *env) start(cmd *cobra.Command, args []string) error {
+	status, err := node.Start(e.ctx)
+	node.ForgetProbes()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(e.stdout, ansi.Color("Network Gateway:", "83+hb"), ansi.Color(status, "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("Edge Daemon:", "83+hb"), ansi.Color(status, "255+hb"))
+
+	e.checkUpdate()
+	return nil
+}
 
-	status, err := node.Stop()
+func (e *env) stop(cmd *cobra.Command, args []string) error {
+	status, err := node.Stop(e.ctx)
+	node.ForgetProbes()
 	if err != nil {
-		utils.PrintError(err)
-	} else {
-		terminal.Println(ansi.Color("Network Gateway:", "83+hb"), ansi.Color(status, "255+hb"))
-		terminal.Println(ansi.Color("Edge Daemon:", "83+hb"), ansi.Color(status, "255+hb"))
+		return err
 	}
+	fmt.Fprintln(e.stdout, ansi.Color("Network Gateway:", "83+hb"), ansi.Color(status, "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("Edge Daemon:", "83+hb"), ansi.Color(status, "255+hb"))
 
-	checkUpdate()
+	e.checkUpdate()
+	return nil
 }
 
-func status(cmd *cobra.Command, args []string) {
+func (e *env) status(cmd *cobra.Command, args []string) error {
 	offline := "NOT ONLINE"
 	online := "ONLINE"
 
 	onlineColor := "83+hb"
 	offlineColor := "196+hb"
 
+	state := make(map[string]string)
 	statusColor := make(map[string]string)
-
-	_, err := node.GetVersion("guardian")
-	guardian := online
-	statusColor["guardian"] = onlineColor
-	if err != nil {
-		guardian = offline
-		statusColor["guardian"] = offlineColor
-	}
-
-	_, err = node.GetVersion("edged")
-	edged := online
-	statusColor["edged"] = onlineColor
-	if err != nil {
-		edged = offline
-		statusColor["edged"] = offlineColor
-	}
-	_, err = node.GetVersion("network-gateway")
-	networkGateway := online
-	statusColor["networkGateway"] = onlineColor
-	if err != nil {
-		networkGateway = offline
-		statusColor["networkGateway"] = offlineColor
+	for _, module := range node.ProbeModules(e.ctx) {
+		name := module.Module.Name
+		state[name] = fmt.Sprintf("%s (%dms)", online, module.Latency/time.Millisecond)
+		statusColor[name] = onlineColor
+		if !module.Online {
+			state[name] = offline
+			statusColor[name] = offlineColor
+		}
 	}
 
-	terminal.Println(ansi.Color("EDGE DAEMON:\t", statusColor["edged"]), ansi.Color(edged, "255+hb"))
-	terminal.Println(ansi.Color("NETWORK GATEWAY:", statusColor["networkGateway"]), ansi.Color(networkGateway, "255+hb"))
-	terminal.Println(ansi.Color("GUARDIAN:\t", statusColor["guardian"]), ansi.Color(guardian, "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("EDGE DAEMON:\t", statusColor["edged"]), ansi.Color(state["edged"], "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("NETWORK GATEWAY:", statusColor["network-gateway"]), ansi.Color(state["network-gateway"], "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("GUARDIAN:\t", statusColor["guardian"]), ansi.Color(state["guardian"], "255+hb"))
 
-	checkUpdate()
+	e.checkUpdate()
+	return nil
 }
 
-func update(cmd *cobra.Command, args []string) {
-	checkUpdate()
+func (e *env) update(cmd *cobra.Command, args []string) error {
+	updateNeeded, err := node.NeedUpdate(e.ctx, 0)
+	if updateNeeded {
+		e.printUpdateNotice()
+	} else if err != nil {
+		return err
+	} else {
+		fmt.Fprintln(e.stderr)
+		fmt.Fprintln(e.stderr, "Everything up to date!")
+	}
+	return nil
 }
 
-func checkUpdate() {
-	updateNeeded, _ := node.NeedUpdate()
+// checkUpdate - tell the user when their modules are out of date. Skipped
+// when disabled or when nobody is watching the terminal.
+func (e *env) checkUpdate() {
+	if e.noUpdateCheck || noUpdateCheckEnv() || config.GetBool("UpdateCheck.Disabled") {
+		return
+	}
+	if !e.interactive {
+		return
+	}
+
+	maxAge := time.Duration(config.GetInt("UpdateCheck.CacheHours")) * time.Hour
+	updateNeeded, err := node.NeedUpdate(e.ctx, maxAge)
 	if updateNeeded {
-		fmt.Println()
-		fmt.Println("One or more of your modules is out of date!")
-		fmt.Println("You can find the newest versions here: https://github.com/gladiusio/gladius-node")
-	} else {
-		fmt.Println()
-		fmt.Println("Everything up to date!")
+		e.printUpdateNotice()
+	} else if err != nil {
+		log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "checkUpdate"}).Debug(err)
 	}
 }
 
-func init() {
-	surveyCore.QuestionIcon = "[Gladius]"
-
-	// register all commands
-	// rootCmd.AddCommand(cmdCreate)
-	rootCmd.AddCommand(cmdApply)
-	rootCmd.AddCommand(cmdCheck)
-	rootCmd.AddCommand(cmdStatus)
-	rootCmd.AddCommand(cmdProfile)
-	rootCmd.AddCommand(cmdVersion)
-	rootCmd.AddCommand(cmdStart)
-	rootCmd.AddCommand(cmdStop)
-	rootCmd.AddCommand(cmdUnlock)
-	rootCmd.AddCommand(cmdUpdate)
+// noUpdateCheckEnv - GLADIUS_NO_UPDATE_CHECK is true (1, t, true...), a
+// value that isn't a boolean is ignored with a warning
+func noUpdateCheckEnv() bool {
+	value := os.Getenv("GLADIUS_NO_UPDATE_CHECK")
+	if value == "" {
+		return false
+	}
 
-	// register all flags
-	// cmdCreate.Flags().BoolVarP(&reset, "reset", "r", false, "reset wallet")
-	// rootCmd.PersistentFlags().BoolVarP(&debug, "debug", "d", false, "debug mode")
-	rootCmd.PersistentFlags().IntVarP(&utils.LogLevel, "level", "l", 2, "set the logging level")
-	rootCmd.PersistentFlags().IntVarP(&utils.RequestTimeout, "timeout", "t", 10, "set the timeout for requests in seconds")
+	disabled, err := strconv.ParseBool(value)
+	if err != nil {
+		log.WithFields(log.Fields{"file": "nodeCommands.go", "func": "noUpdateCheckEnv", "value": value}).Warning("GLADIUS_NO_UPDATE_CHECK is neither true nor false, checking for updates")
+		return false
+	}
+	return disabled
+}
+
+// printUpdateNotice - written to stderr so it never ends up in output that
+// is parsed
+func (e *env) printUpdateNotice() {
+	fmt.Fprintln(e.stderr)
+	fmt.Fprintln(e.stderr, "One or more of your modules is out of date!")
+	fmt.Fprintln(e.stderr, "You can find the newest versions here: https://github.com/gladiusio/gladius-node")
 }
@@ -0,0 +1,27 @@
+package commands
+
+import "testing"
+
+func TestNoUpdateCheckEnv(t *testing.T) {
+	tests := []struct {
+		value    string
+		disabled bool
+	}{
+		{"", false},
+		{"1", true},
+		{"true", true},
+		{"TRUE", true},
+		{"t", true},
+		{"0", false},
+		{"false", false},
+		{"no", false},
+		{"yes", false},
+	}
+
+	for _, test := range tests {
+		t.Setenv("GLADIUS_NO_UPDATE_CHECK", test.value)
+		if got := noUpdateCheckEnv(); got != test.disabled {
+			t.Errorf("GLADIUS_NO_UPDATE_CHECK=%q disables the check: %v, want %v", test.value, got, test.disabled)
+		}
+	}
+}
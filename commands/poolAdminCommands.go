@@ -0,0 +1,536 @@
+package commands
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gladiusio/gladius-cli/node"
+	"github.com/gladiusio/gladius-cli/utils"
+	"github.com/mgutz/ansi"
+	"github.com/spf13/cobra"
+	survey "gopkg.in/AlecAivazis/survey.v1"
+)
+
+// applicationFilter - which applications list shows
+type applicationFilter struct {
+	status       string  // one of the node.Status constants, "" for any
+	country      string  // name or code of a country, "" for any
+	minBandwidth float64 // Mbps, 0 for no minimum
+	maxBandwidth float64 // Mbps, 0 for no maximum
+}
+
+// decision - approve or reject the application of a node
+type decision struct {
+	node    utils.Address
+	approve bool
+	message string
+	line    int // of the CSV it was read from, 0 for arguments
+}
+
+// where - the node, with the line of the CSV it comes from
+func (d decision) where() string {
+	if d.line > 0 {
+		return fmt.Sprintf("line %d: %s", d.line, d.node)
+	}
+	return d.node.String()
+}
+
+// poolAdminCommand - the commands for running a pool
+func (e *env) poolAdminCommand() *cobra.Command {
+	var pool, message string
+	var filter applicationFilter
+	var listJSON, decideYes bool
+
+	cmdPoolAdmin := &cobra.Command{
+		Use:   "pool-admin",
+		Short: "Manage a pool you run",
+		Long:  "Commands for the operators of a pool, using the pool's account in the Network Gateway",
+	}
+
+	cmdApplications := &cobra.Command{
+		Use:   "applications",
+		Short: "Review and decide the applications of nodes",
+		Long:  "See the applications nodes sent to your pool, and approve or reject them",
+	}
+	cmdApplications.PersistentFlags().StringVar(&pool, "pool", "", "address or alias of your pool (required)")
+
+	cmdList := &cobra.Command{
+		Annotations: requires("/api/pool/applications/<pool>/list"),
+		Use:         "list",
+		Short:       "See the applications to your pool",
+		Long:        "List the applications to your pool, filtered by status, country or bandwidth",
+		Example:     "  gladius pool-admin applications list --pool home --status pending --country DE --min-bandwidth 100",
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return e.listApplications(pool, filter, listJSON)
+		},
+	}
+	cmdList.Flags().StringVar(&filter.status, "status", "", "only show pending, approved or rejected applications")
+	cmdList.Flags().StringVar(&filter.country, "country", "", "only show nodes in this country, by name or ISO code")
+	cmdList.Flags().Float64Var(&filter.minBandwidth, "min-bandwidth", 0, "only show nodes with at least this bandwidth (Mbps)")
+	cmdList.Flags().Float64Var(&filter.maxBandwidth, "max-bandwidth", 0, "only show nodes with at most this bandwidth (Mbps)")
+	cmdList.Flags().BoolVar(&listJSON, "json", false, "print the applications with their profiles as JSON")
+
+	cmdShow := &cobra.Command{
+		Annotations: requires("/api/pool/applications/<pool>/list"),
+		Use:         "show <node>",
+		Short:       "See the application of a node",
+		Long:        "Show the status and every answer of the application of a node to your pool",
+		Args:        cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return e.showApplication(pool, args[0])
+		},
+	}
+
+	cmdApprove := &cobra.Command{
+		Annotations: requires("/api/pool/applications/<pool>/list", "/api/pool/applications/<pool>/<node>/approve"),
+		Use:         "approve <node>...",
+		Short:       "Approve applications to your pool",
+		Long:        "Approve the applications of the nodes given, optionally with a message for them",
+		Example:     "  gladius pool-admin applications approve --pool home 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed --message \"Welcome!\"",
+		Args:        cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return e.decideNodes(pool, args, true, message)
+		},
+	}
+
+	cmdReject := &cobra.Command{
+		Annotations: requires("/api/pool/applications/<pool>/list", "/api/pool/applications/<pool>/<node>/reject"),
+		Use:         "reject <node>...",
+		Short:       "Reject applications to your pool",
+		Long:        "Reject the applications of the nodes given, optionally with a message for them",
+		Args:        cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return e.decideNodes(pool, args, false, message)
+		},
+	}
+	for _, cmd := range []*cobra.Command{cmdApprove, cmdReject} {
+		cmd.Flags().StringVar(&message, "message", "", "message sent to the nodes with the decision")
+	}
+
+	cmdDecide := &cobra.Command{
+		Annotations: requires("/api/pool/applications/<pool>/list", "/api/pool/applications/<pool>/<node>/approve", "/api/pool/applications/<pool>/<node>/reject"),
+		Use:         "decide <file.csv>",
+		Short:       "Approve and reject applications listed in a CSV file",
+		Long: "Decide many applications at once. Each row of the CSV file is a node address, approve or reject, and an optional message. " +
+			"A first row naming the columns (node, decision, message) is allowed. Every row is checked before anything is sent",
+		Example: "  gladius pool-admin applications decide --pool home decisions.csv",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return e.decideFile(pool, args[0], decideYes)
+		},
+	}
+	cmdDecide.Flags().BoolVarP(&decideYes, "yes", "y", false, "don't ask for confirmation")
+
+	// decisions send transactions from the pool's account
+	e.locked(cmdApprove)
+	e.locked(cmdReject)
+	e.locked(cmdDecide)
+
+	cmdApplications.AddCommand(cmdList)
+	cmdApplications.AddCommand(cmdShow)
+	cmdApplications.AddCommand(cmdApprove)
+	cmdApplications.AddCommand(cmdReject)
+	cmdApplications.AddCommand(cmdDecide)
+	cmdPoolAdmin.AddCommand(cmdApplications)
+
+	return cmdPoolAdmin
+}
+
+// adminPool - the pool given with --pool
+func (e *env) adminPool(pool, path string) (utils.Address, error) {
+	if pool == "" {
+		return "", utils.HandleError(errors.New("no pool"), "Give the address or alias of your pool with --pool", path)
+	}
+	return e.parsePool(pool, path)
+}
+
+// matches - the application passes the filter
+func (f applicationFilter) matches(app node.PoolApplication) bool {
+	if f.status != "" && app.Status() != f.status {
+		return false
+	}
+
+	if f.country != "" {
+		wanted, _ := utils.FindCountry(f.country)
+		country, ok := applicationCountry(app)
+		if !ok || country.Code != wanted.Code {
+			return false
+		}
+	}
+
+	if f.minBandwidth > 0 || f.maxBandwidth > 0 {
+		bandwidth, ok := applicationBandwidth(app)
+		if !ok || (f.minBandwidth > 0 && bandwidth < f.minBandwidth) || (f.maxBandwidth > 0 && bandwidth > f.maxBandwidth) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// check - the filter only has values list understands
+func (f applicationFilter) check() error {
+	switch f.status {
+	case "", node.StatusPending, node.StatusApproved, node.StatusRejected:
+	default:
+		return fmt.Errorf("Unknown status %s, use pending, approved or rejected", f.status)
+	}
+	if _, ok := utils.FindCountry(f.country); f.country != "" && !ok {
+		return fmt.Errorf("Unknown country %s, use its English name or ISO code", f.country)
+	}
+	return nil
+}
+
+// applicationCountry - the country of the node, from the ISO code sent by
+// this CLI or else from the country typed by the user of an older one
+func applicationCountry(app node.PoolApplication) (utils.Country, bool) {
+	if code, ok := app.Profile["locationCode"].(string); ok {
+		if country, ok := utils.FindCountry(code); ok {
+			return country, true
+		}
+	}
+	if location, ok := app.Profile["location"].(string); ok {
+		return utils.FindCountry(location)
+	}
+	return utils.Country{}, false
+}
+
+// applicationBandwidth - the bandwidth of the node in Mbps, sent as a number
+// or a string depending on the form
+func applicationBandwidth(app node.PoolApplication) (float64, bool) {
+	speed, ok := app.Profile["estimatedSpeed"]
+	if !ok {
+		return 0, false
+	}
+	bandwidth, err := strconv.ParseFloat(strings.TrimSpace(fmt.Sprint(speed)), 64)
+	return bandwidth, err == nil
+}
+
+// statusColor - how a status is printed
+func statusColor(status string) string {
+	switch status {
+	case node.StatusApproved:
+		return "83+hb"
+	case node.StatusRejected:
+		return "196+hb"
+	}
+	return "214+hb"
+}
+
+func (e *env) listApplications(pool string, filter applicationFilter, asJSON bool) error {
+	err := filter.check()
+	if err != nil {
+		return utils.HandleError(err, err.Error(), "commands.listApplications")
+	}
+
+	poolAddress, err := e.adminPool(pool, "commands.listApplications")
+	if err != nil {
+		return err
+	}
+
+	err = node.RequireGateway(e.ctx)
+	if err != nil {
+		return err
+	}
+
+	applications, err := node.GetPoolApplications(e.ctx, poolAddress.String())
+	if err != nil {
+		return err
+	}
+
+	shown := []node.PoolApplication{}
+	for _, app := range applications {
+		if filter.matches(app) {
+			shown = append(shown, app)
+		}
+	}
+
+	if asJSON {
+		b, _ := json.MarshalIndent(shown, "", "  ")
+		fmt.Fprintln(e.stdout, string(b))
+		return nil
+	}
+
+	if len(shown) == 0 {
+		fmt.Fprintln(e.stdout, ansi.Color("No applications to "+poolAddress.String()+" match", "255+hb"))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(e.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NODE\tSTATUS\tNAME\tCOUNTRY\tBANDWIDTH")
+	for _, app := range shown {
+		country := "-"
+		if c, ok := applicationCountry(app); ok {
+			country = c.Code
+		}
+		bandwidth := "-"
+		if b, ok := applicationBandwidth(app); ok {
+			bandwidth = strconv.FormatFloat(b, 'f', -1, 64) + " Mbps"
+		}
+		name := "-"
+		if n, ok := app.Profile["name"]; ok {
+			name = fmt.Sprint(n)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", app.Node, app.Status(), name, country, bandwidth)
+	}
+	w.Flush()
+
+	e.checkUpdate()
+	return nil
+}
+
+func (e *env) showApplication(pool, nodeArg string) error {
+	poolAddress, err := e.adminPool(pool, "commands.showApplication")
+	if err != nil {
+		return err
+	}
+	nodeAddress, err := e.parseAddress(nodeArg, "commands.showApplication")
+	if err != nil {
+		return err
+	}
+
+	err = node.RequireGateway(e.ctx)
+	if err != nil {
+		return err
+	}
+
+	applications, err := node.GetPoolApplications(e.ctx, poolAddress.String())
+	if err != nil {
+		return err
+	}
+
+	app, ok := findApplication(applications, nodeAddress)
+	if !ok {
+		return utils.HandleError(errors.New("no application from "+nodeAddress.String()), "There is no application from "+nodeAddress.String()+" to "+poolAddress.String(), "commands.showApplication")
+	}
+
+	fmt.Fprintln(e.stdout, ansi.Color("NODE:", "83+hb"), ansi.Color(nodeAddress.String(), "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("STATUS:", "83+hb"), ansi.Color(app.Status(), statusColor(app.Status())))
+	if app.Message != "" {
+		fmt.Fprintln(e.stdout, ansi.Color("MESSAGE:", "83+hb"), ansi.Color(app.Message, "255+hb"))
+	}
+
+	var keys []string
+	for key := range app.Profile {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintln(e.stdout)
+	for _, key := range keys {
+		answer := app.Profile[key]
+		if choices := toStrings(answer); choices != nil {
+			answer = strings.Join(choices, ", ")
+		}
+		fmt.Fprintln(e.stdout, ansi.Color(key+":", "83+hb"), ansi.Color(fmt.Sprint(answer), "255+hb"))
+	}
+
+	e.checkUpdate()
+	return nil
+}
+
+// findApplication - the application sent by a node
+func findApplication(applications []node.PoolApplication, nodeAddress utils.Address) (node.PoolApplication, bool) {
+	for _, app := range applications {
+		if strings.EqualFold(app.Node, nodeAddress.String()) {
+			return app, true
+		}
+	}
+	return node.PoolApplication{}, false
+}
+
+func (e *env) decideNodes(pool string, nodes []string, approve bool, message string) error {
+	poolAddress, err := e.adminPool(pool, "commands.decideNodes")
+	if err != nil {
+		return err
+	}
+
+	var decisions []decision
+	for _, arg := range nodes {
+		nodeAddress, err := e.parseAddress(arg, "commands.decideNodes")
+		if err != nil {
+			return err
+		}
+		decisions = append(decisions, decision{node: nodeAddress, approve: approve, message: message})
+	}
+
+	return e.decide(poolAddress, decisions, false)
+}
+
+func (e *env) decideFile(pool, file string, yes bool) error {
+	poolAddress, err := e.adminPool(pool, "commands.decideFile")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return utils.HandleError(err, "Could not read "+file, "commands.decideFile")
+	}
+	defer f.Close()
+
+	decisions, err := readDecisions(f)
+	if err != nil {
+		return utils.HandleError(err, file+": "+err.Error(), "commands.decideFile")
+	}
+	if len(decisions) == 0 {
+		return utils.HandleError(errors.New("no decisions"), "There are no decisions in "+file, "commands.decideFile")
+	}
+
+	return e.decide(poolAddress, decisions, !yes)
+}
+
+// readDecisions - the rows of a decisions CSV: node, approve or reject,
+// message. The columns can be in another order when the first row names them.
+func readDecisions(r io.Reader) ([]decision, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	columns := map[string]int{"node": 0, "decision": 1, "message": 2}
+	var decisions []decision
+	for line := 1; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if line == 1 {
+			header := make(map[string]int)
+			for i, name := range row {
+				header[strings.ToLower(strings.TrimSpace(name))] = i
+			}
+			if _, ok := header["node"]; ok {
+				if _, ok := header["decision"]; !ok {
+					return nil, errors.New("the first row names the columns but has no decision column")
+				}
+				columns = header
+				continue
+			}
+		}
+
+		cell := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[i])
+		}
+
+		address, _, err := utils.ParseAddress(cell("node"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err)
+		}
+
+		d := decision{node: address, message: cell("message"), line: line}
+		switch strings.ToLower(cell("decision")) {
+		case "approve", "approved":
+			d.approve = true
+		case "reject", "rejected":
+		default:
+			return nil, fmt.Errorf("line %d: the decision is %q, use approve or reject", line, cell("decision"))
+		}
+		decisions = append(decisions, d)
+	}
+
+	return decisions, nil
+}
+
+// decide - send decisions, after checking every node applied to the pool.
+// Decisions that are already made are skipped; a decision that fails doesn't
+// stop the others.
+func (e *env) decide(poolAddress utils.Address, decisions []decision, confirm bool) error {
+	err := node.RequireGateway(e.ctx)
+	if err != nil {
+		return err
+	}
+
+	applications, err := node.GetPoolApplications(e.ctx, poolAddress.String())
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	var todo []decision
+	approvals := 0
+	for _, d := range decisions {
+		where := d.where()
+		app, ok := findApplication(applications, d.node)
+		if !ok {
+			problems = append(problems, where+" did not apply to "+poolAddress.String())
+			continue
+		}
+		if (d.approve && app.Status() == node.StatusApproved) || (!d.approve && app.Status() == node.StatusRejected) {
+			warning := fmt.Sprintf("%s is already %s, skipping it", where, app.Status())
+			fmt.Fprintln(e.stderr, ansi.Color("[WARNING] ", "214+hb")+ansi.Color(warning, "255+hb"))
+			continue
+		}
+
+		todo = append(todo, d)
+		if d.approve {
+			approvals++
+		}
+	}
+	if len(problems) > 0 {
+		return utils.HandleError(errors.New(strings.Join(problems, "; ")), "Nothing was sent, "+strings.Join(problems, "; "), "commands.decide")
+	}
+	if len(todo) == 0 {
+		fmt.Fprintln(e.stdout, ansi.Color("Nothing to do, every application is already decided", "255+hb"))
+		return nil
+	}
+
+	if confirm {
+		send := false
+		err = e.prompter.AskOne(&survey.Confirm{
+			Message: fmt.Sprintf("Approve %d and reject %d applications to %s?", approvals, len(todo)-approvals, poolAddress),
+		}, &send, nil)
+		if err != nil {
+			return err
+		}
+		if !send {
+			fmt.Fprintln(e.stdout, ansi.Color("Nothing was sent", "255+hb"))
+			return nil
+		}
+	}
+
+	var failed, failures []string
+	for _, d := range todo {
+		tx, err := node.DecideApplication(e.ctx, poolAddress.String(), d.node.String(), d.approve, d.message)
+		if err != nil {
+			failed = append(failed, d.where())
+			failures = append(failures, d.where()+": "+err.Error())
+			fmt.Fprintln(e.stderr, ansi.Color("[ERROR] ", "196+hb")+ansi.Color(d.where()+": "+err.Error(), "255+hb"))
+			continue
+		}
+
+		label, color := "REJECTED:", statusColor(node.StatusRejected)
+		if d.approve {
+			label, color = "APPROVED:", statusColor(node.StatusApproved)
+		}
+		result := d.node.String()
+		if tx != "" {
+			result += " (tx " + tx + ")"
+		}
+		fmt.Fprintln(e.stdout, ansi.Color(label, color), ansi.Color(result, "255+hb"))
+	}
+
+	if len(failed) > 0 {
+		msg := fmt.Sprintf("%d of %d decisions could not be sent (%s), the others were sent", len(failed), len(todo), strings.Join(failed, "; "))
+		return utils.HandleError(errors.New(strings.Join(failures, "; ")), msg, "commands.decide")
+	}
+
+	e.checkUpdate()
+	return nil
+}
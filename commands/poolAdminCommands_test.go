@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gladiusio/gladius-cli/mock"
+	"github.com/gladiusio/gladius-cli/utils"
+)
+
+const otherNode = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+
+func TestReadDecisions(t *testing.T) {
+	type row struct {
+		node    string
+		approve bool
+		message string
+		line    int
+	}
+	tests := []struct {
+		name string
+		csv  string
+		rows []row
+		err  string // part of the error, none when empty
+	}{
+		{
+			name: "no header",
+			csv:  testNode + ",approve,Welcome!\n" + otherNode + ", rejected\n",
+			rows: []row{{testNode, true, "Welcome!", 1}, {otherNode, false, "", 2}},
+		},
+		{
+			name: "header in another order",
+			csv:  "Message, Decision, Node\nWelcome!,APPROVE," + testNode + "\n,reject," + otherNode + "\n",
+			rows: []row{{testNode, true, "Welcome!", 2}, {otherNode, false, "", 3}},
+		},
+		{
+			name: "header without message",
+			csv:  "decision,node\napprove," + testNode + "\n",
+			rows: []row{{testNode, true, "", 2}},
+		},
+		{
+			name: "header without decision",
+			csv:  "node,message\n" + testNode + ",Welcome!\n",
+			err:  "no decision column",
+		},
+		{
+			name: "bad address",
+			csv:  testNode + ",approve\n0x1234,reject\n",
+			err:  "line 2:",
+		},
+		{
+			name: "bad address after a header",
+			csv:  "node,decision\n" + testNode + ",approve\n" + testNode + ",reject\nnot an address,approve\n",
+			err:  "line 4:",
+		},
+		{
+			name: "bad checksum",
+			csv:  strings.Replace(testNode, "fB", "Fb", 1) + ",approve\n",
+			err:  "line 1:",
+		},
+		{
+			name: "bad decision",
+			csv:  testNode + ",maybe\n",
+			err:  `line 1: the decision is "maybe"`,
+		},
+		{
+			name: "empty",
+			csv:  "",
+		},
+	}
+
+	for _, test := range tests {
+		decisions, err := readDecisions(strings.NewReader(test.csv))
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: error %v, want %q", test.name, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+
+		var rows []row
+		for _, d := range decisions {
+			rows = append(rows, row{d.node.String(), d.approve, d.message, d.line})
+		}
+		if len(rows) != len(test.rows) {
+			t.Errorf("%s: read %+v, want %+v", test.name, rows, test.rows)
+			continue
+		}
+		for i := range rows {
+			if rows[i] != test.rows[i] {
+				t.Errorf("%s: row %d is %+v, want %+v", test.name, i, rows[i], test.rows[i])
+			}
+		}
+	}
+}
+
+// doerFunc - a client answering with a function
+type doerFunc func(req *http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// a decision that fails is reported with its line, the others are sent
+func TestMockDecidePartialFailure(t *testing.T) {
+	state := mock.NewState()
+	state.Locked = false
+	state.AddReceived(testPool, 3)
+	daemon := startMock(t, state)
+
+	var nodes []string
+	for address := range state.Received[strings.ToLower(testPool)] {
+		nodes = append(nodes, address)
+	}
+	failing := nodes[1]
+
+	csv := "node,decision\n" + nodes[0] + ",approve\n" + failing + ",approve\n" + nodes[2] + ",reject\n"
+	file := filepath.Join(t.TempDir(), "decisions.csv")
+	err := ioutil.WriteFile(file, []byte(csv), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := doerFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method == "POST" && strings.Contains(strings.ToLower(req.URL.Path), failing) {
+			return nil, errors.New("connection reset by peer")
+		}
+		return http.DefaultClient.Do(req)
+	})
+	output, err := run(t, client, "", "pool-admin", "applications", "decide", "--pool", testPool, file, "--yes", "--no-retry")
+
+	response, ok := err.(*utils.ErrorResponse)
+	if !ok {
+		t.Fatalf("error %v, want an ErrorResponse\n%s", err, output)
+	}
+	mustContain(t, response.Message(), "1 of 3 decisions could not be sent", "line 3: ")
+	mustContain(t, response.LogError, "connection reset by peer")
+	if response.Path != "commands.decide" {
+		t.Errorf("error path %q", response.Path)
+	}
+
+	received := getState(t, daemon).Received[strings.ToLower(testPool)]
+	if app := received[nodes[0]]; app.Pending || !app.Approved {
+		t.Errorf("line 2 wasn't sent: %+v", app)
+	}
+	if app := received[failing]; !app.Pending {
+		t.Errorf("the failing line was decided: %+v", app)
+	}
+	if app := received[nodes[2]]; app.Pending || app.Approved {
+		t.Errorf("line 4 wasn't sent: %+v", app)
+	}
+}
@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gladiusio/gladius-cli/config"
+	"github.com/gladiusio/gladius-cli/utils"
+	"github.com/mgutz/ansi"
+	"github.com/spf13/cobra"
+)
+
+// poolsCommand - commands for the pools the user works with
+func (e *env) poolsCommand() *cobra.Command {
+	cmdPools := &cobra.Command{
+		Use:   "pools",
+		Short: "Manage the pools you work with",
+		Long:  "Give pools names you can type instead of their address",
+	}
+
+	cmdAlias := &cobra.Command{
+		Use:   "alias",
+		Short: "Manage pool aliases",
+		Long:  "Aliases are names of pool addresses, accepted by every command asking for a pool. They are kept in the CLI config",
+	}
+
+	cmdAliasAdd := &cobra.Command{
+		Use:     "add <name> <address>",
+		Short:   "Add or change a pool alias",
+		Long:    "Save name as an alias of a pool address",
+		Example: "  gladius pools alias add home 0xC88a29cf8F0Baf07fc822DEaA24b383Fc30f27e4",
+		Args:    cobra.ExactArgs(2),
+		RunE:    e.poolAliasAdd,
+	}
+
+	cmdAliasRm := &cobra.Command{
+		Use:   "rm <name>",
+		Short: "Remove a pool alias",
+		Long:  "Remove a pool alias from the CLI config",
+		Args:  cobra.ExactArgs(1),
+		RunE:  e.poolAliasRm,
+	}
+
+	cmdAliasList := &cobra.Command{
+		Use:   "list",
+		Short: "See the pool aliases",
+		Long:  "Show every pool alias and the pool it stands for",
+		Args:  cobra.NoArgs,
+		RunE:  e.poolAliasList,
+	}
+
+	cmdAlias.AddCommand(cmdAliasAdd)
+	cmdAlias.AddCommand(cmdAliasRm)
+	cmdAlias.AddCommand(cmdAliasList)
+	cmdPools.AddCommand(cmdAlias)
+
+	return cmdPools
+}
+
+func (e *env) poolAliasAdd(cmd *cobra.Command, args []string) error {
+	address, err := e.parseAddress(args[1], "commands.poolAliasAdd")
+	if err != nil {
+		return err
+	}
+
+	err = config.SetPoolAlias(args[0], address.String())
+	if err != nil {
+		return utils.HandleError(err, err.Error(), "commands.poolAliasAdd")
+	}
+
+	fmt.Fprintln(e.stdout, ansi.Color("Alias saved:", "83+hb"), ansi.Color(strings.ToLower(args[0])+" = "+address.String(), "255+hb"))
+	return nil
+}
+
+func (e *env) poolAliasRm(cmd *cobra.Command, args []string) error {
+	err := config.RemovePoolAlias(args[0])
+	if err != nil {
+		return utils.HandleError(err, err.Error(), "commands.poolAliasRm")
+	}
+
+	fmt.Fprintln(e.stdout, ansi.Color("Alias removed:", "83+hb"), ansi.Color(args[0], "255+hb"))
+	return nil
+}
+
+func (e *env) poolAliasList(cmd *cobra.Command, args []string) error {
+	aliases := config.PoolAliases()
+	if len(aliases) == 0 {
+		fmt.Fprintln(e.stdout, ansi.Color("No pool aliases yet, add one with", "255+hb"), ansi.Color("gladius pools alias add <name> <address>", "83+hb"))
+		return nil
+	}
+
+	var names []string
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	last := config.LastPool()
+	for _, name := range names {
+		address := aliases[name]
+		if address == last {
+			address += " (last applied to)"
+		}
+		fmt.Fprintln(e.stdout, ansi.Color(name+":", "83+hb"), ansi.Color(address, "255+hb"))
+	}
+
+	return nil
+}
@@ -1,34 +1,373 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"strings"
+	"time"
 
+	"github.com/gladiusio/gladius-cli/config"
+	"github.com/gladiusio/gladius-cli/node"
+	"github.com/gladiusio/gladius-cli/utils"
+	"github.com/mattn/go-isatty"
+	"github.com/mgutz/ansi"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	surveyCore "gopkg.in/AlecAivazis/survey.v1/core"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
 )
 
-// root command
-var rootCmd = &cobra.Command{
-	Use:   "gladius",
-	Short: "CLI for Gladius Network",
-	Long:  "Gladius CLI. This can be used to interact with various components of the Gladius Network.",
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("\nWelcome to the Gladius CLI!")
-		fmt.Println("\nHere are the commands to setup a node (in order):")
-		fmt.Println("\n$ gladius start")
-		fmt.Println("$ gladius apply")
-		fmt.Println("$ gladius check")
-		fmt.Println("\nAfter you are accepted into a pool you will automatically become an edge node")
-		fmt.Println("\nTo unlock your wallet after it has been created run:")
-		fmt.Println("\n$ gladius unlock")
-		fmt.Println("\nUse the -h flag to see the help menu")
-	},
+// Options - what the commands run with, zero values use the terminal, survey
+// prompts, an HTTP client built from the flags and the system clock
+type Options struct {
+	Context  context.Context  // cancels the requests made by the commands
+	In       io.Reader        // answers to the questions, one per line, when it isn't a terminal
+	Out      io.Writer        // output of the commands and the help
+	Err      io.Writer        // notices and warnings
+	Prompter utils.Prompter   // answers the questions asked by the commands
+	Client   utils.Doer       // sends the requests to the modules
+	Clock    func() time.Time // used for latencies and cache ages
+}
+
+// env - shared by the commands of one tree
+type env struct {
+	ctx         context.Context
+	stdin       io.Reader
+	stdout      io.Writer
+	stderr      io.Writer
+	prompter    utils.Prompter
+	client      utils.Doer
+	clock       func() time.Time
+	interactive bool // a user is watching stdout and typing on stdin
+
+	background  context.Context // the context given in the options, ctx adds the client of the current run
+	releaseLock func()          // releases the lock held by a locked command before it returns
+	flags       *pflag.FlagSet  // the flags of every command, to tell which ones were set
+
+	baseDir         string
+	noRetry         bool
+	noUpdateCheck   bool
+	waitLock        bool
+	trace           bool
+	traceHAR        string
+	recordPath      string
+	replayPath      string
+	requestTimeout  int
+	connectTimeout  int
+	responseTimeout int
+}
+
+func newEnv(opts Options) *env {
+	e := &env{
+		ctx:         opts.Context,
+		stdin:       opts.In,
+		stdout:      opts.Out,
+		stderr:      opts.Err,
+		prompter:    opts.Prompter,
+		client:      opts.Client,
+		clock:       opts.Clock,
+		releaseLock: func() {},
+	}
+
+	if e.ctx == nil {
+		e.ctx = context.Background()
+	}
+	e.background = e.ctx
+	if e.stdin == nil {
+		e.stdin = os.Stdin
+	}
+	if e.stdout == nil {
+		e.stdout = terminal.NewAnsiStdout()
+		e.interactive = isTerminal(os.Stdout)
+	} else {
+		e.interactive = isTerminal(e.stdout)
+	}
+	e.interactive = e.interactive && isTerminal(e.stdin)
+	if e.stderr == nil {
+		e.stderr = terminal.NewAnsiStderr()
+	}
+	if e.prompter == nil {
+		if isTerminal(e.stdin) {
+			e.prompter = utils.SurveyPrompter{}
+		} else {
+			e.prompter = utils.NewLinePrompter(e.stdin, e.stdout)
+		}
+	}
+	if e.clock == nil {
+		e.clock = time.Now
+	}
+
+	return e
+}
+
+// install - make the packages used by the commands print, ask and send
+// requests through the env. The client is built for every run as the flags
+// may differ, and travels with the context.
+func (e *env) install(retry utils.RetryPolicy) error {
+	utils.Stdout = e.stdout
+	utils.Stderr = e.stderr
+	utils.Prompt = e.prompter
+	utils.Now = e.clock
+
+	client := e.client
+	if client == nil {
+		var err error
+		client, err = utils.NewClient(utils.ClientOptions{
+			RequestTimeout:        time.Duration(e.requestTimeout) * time.Second,
+			ConnectTimeout:        time.Duration(e.connectTimeout) * time.Second,
+			ResponseHeaderTimeout: time.Duration(e.responseTimeout) * time.Second,
+			Retry:                 retry,
+			Trace:                 e.trace,
+			TraceHAR:              e.traceHAR,
+			RecordPath:            e.recordPath,
+			ReplayPath:            e.replayPath,
+			RedactFields:          config.GetStringSlice("Trace.RedactFields"),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	e.ctx = utils.WithClient(e.background, client)
+
+	// the modules answered another client
+	node.ForgetProbes()
+	return nil
+}
+
+// retryPolicy - the retry policy of cfg, unless --retries or --no-retry say
+// otherwise
+func (e *env) retryPolicy(cfg config.Config) utils.RetryPolicy {
+	retry := utils.RetryPolicy{
+		Attempts:         cfg.Retry.Attempts,
+		BaseDelay:        time.Duration(cfg.Retry.BaseDelayMS) * time.Millisecond,
+		MaxDelay:         time.Duration(cfg.Retry.MaxDelayMS) * time.Millisecond,
+		BreakerThreshold: cfg.Retry.BreakerThreshold,
+		BreakerCooldown:  time.Duration(cfg.Retry.BreakerCooldownSeconds) * time.Second,
+	}
+	if e.flags.Changed("retries") {
+		retry.Attempts, _ = e.flags.GetInt("retries")
+	}
+	if e.noRetry {
+		retry.Attempts = 1
+		retry.BreakerThreshold = 0
+	}
+	return retry
+}
+
+// watchConfig - follow the changes of the config file while a long-running
+// command runs: the log level, the ports and the retry policy change without
+// restarting it. check subscribers run first and can refuse a change. Call
+// the returned function once done.
+func (e *env) watchConfig(check ...config.Subscriber) func() {
+	var unsubscribe []func()
+	for _, fn := range check {
+		unsubscribe = append(unsubscribe, config.Subscribe(fn))
+	}
+
+	unsubscribe = append(unsubscribe,
+		config.Subscribe(func(previous, next config.Config) error {
+			if e.flags.Changed("level") || previous.Log.Level == next.Log.Level {
+				return nil
+			}
+			level, err := utils.ParseLogLevel(next.Log.Level)
+			if err != nil {
+				return fmt.Errorf("Log.Level: %s", err)
+			}
+			log.SetLevel(level)
+			return nil
+		}),
+		config.Subscribe(func(previous, next config.Config) error {
+			// the modules are probed again on their new ports
+			if previous.Ports != next.Ports {
+				node.ForgetProbes()
+			}
+			return nil
+		}),
+		config.Subscribe(func(previous, next config.Config) error {
+			utils.SetRetryPolicy(e.ctx, e.retryPolicy(next))
+			return nil
+		}),
+	)
+
+	stop, err := config.Watch()
+	if err != nil {
+		log.WithFields(log.Fields{"file": "root.go", "func": "watchConfig"}).Debug("Not following config changes: ", err)
+		stop = func() {}
+	}
+
+	return func() {
+		stop()
+		for _, fn := range unsubscribe {
+			fn()
+		}
+	}
+}
+
+func isTerminal(v interface{}) bool {
+	f, ok := v.(*os.File)
+	return ok && isatty.IsTerminal(f.Fd())
+}
+
+// NewRootCommand - the gladius command and all its subcommands. Other
+// programs can mount it with AddCommand. Errors are returned by Execute and
+// not printed. Every tree sends its requests through its own client, but
+// only one should run at a time as the logger and the prompter are shared.
+func NewRootCommand(opts Options) *cobra.Command {
+	e := newEnv(opts)
+
+	rootCmd := &cobra.Command{
+		Use:           "gladius",
+		Short:         "CLI for Gladius Network",
+		Long:          "Gladius CLI. This can be used to interact with various components of the Gladius Network.",
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// the flags parsed, from here on errors are not usage errors
+			cmd.SilenceUsage = true
+
+			// setup config handling
+			config.SetBaseDir(e.baseDir)
+			err := config.SetupConfig("gladius-cli", config.CLIDefaults())
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.Get()
+			if err != nil {
+				return err
+			}
+
+			if e.recordPath != "" && e.replayPath != "" {
+				return errors.New("--record and --replay can't be used together")
+			}
+			err = e.install(e.retryPolicy(cfg))
+			if err != nil {
+				return err
+			}
+
+			// --level, or else the config
+			utils.LogLevel = cfg.Log.Level
+			err = utils.SetupLogger(cmd.CommandPath())
+			if err != nil {
+				return err
+			}
+
+			// make sure the running modules implement what the command calls
+			if endpoints := cmd.Annotations["endpoints"]; endpoints != "" {
+				warnings, err := node.CheckEndpoints(e.ctx, strings.Split(endpoints, ","))
+				for _, warning := range warnings {
+					log.WithFields(log.Fields{"file": "root.go", "func": "PersistentPreRunE"}).Warning(warning)
+					fmt.Fprintln(e.stderr, ansi.Color("[WARNING] ", "214+hb")+ansi.Color(warning, "255+hb"))
+				}
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			utils.LogFile.Close()
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprintln(e.stdout, "\nWelcome to the Gladius CLI!")
+			fmt.Fprintln(e.stdout, "\nHere are the commands to setup a node (in order):")
+			fmt.Fprintln(e.stdout, "\n$ gladius start")
+			fmt.Fprintln(e.stdout, "$ gladius apply")
+			fmt.Fprintln(e.stdout, "$ gladius check")
+			fmt.Fprintln(e.stdout, "\nAfter you are accepted into a pool you will automatically become an edge node")
+			fmt.Fprintln(e.stdout, "\nTo unlock your wallet after it has been created run:")
+			fmt.Fprintln(e.stdout, "\n$ gladius unlock")
+			fmt.Fprintln(e.stdout, "\nUse the -h flag to see the help menu")
+		},
+	}
+	rootCmd.SetOutput(e.stdout)
+
+	// register all commands
+	rootCmd.AddCommand(e.nodeCommands()...)
+	rootCmd.AddCommand(e.walletCommands()...)
+	rootCmd.AddCommand(e.txCommand())
+	rootCmd.AddCommand(e.configCommand())
+	rootCmd.AddCommand(e.poolsCommand())
+	rootCmd.AddCommand(e.poolAdminCommand())
+	rootCmd.AddCommand(e.devCommand())
+
+	// register all flags
+	// rootCmd.PersistentFlags().BoolVarP(&debug, "debug", "d", false, "debug mode")
+	rootCmd.PersistentFlags().StringVar(&e.baseDir, "base-dir", "", "keep the config, logs and state of the CLI in this directory (default is $GLADIUSBASE, or the XDG directories on Linux and ~/.gladius elsewhere)")
+	rootCmd.PersistentFlags().StringVarP(&utils.LogLevel, "level", "l", "info", "set the logging level (debug, info, warn, error)")
+	rootCmd.PersistentFlags().StringVar(&utils.LogFormat, "log-format", "text", "set the log format (text, json)")
+	rootCmd.PersistentFlags().StringVar(&utils.LogPath, "log-file", "", "write logs to this file, or \"stderr\" (default is the rotated log in DirLogs)")
+	rootCmd.PersistentFlags().BoolVar(&e.trace, "trace", false, "log every request to the daemons (secrets redacted)")
+	rootCmd.PersistentFlags().StringVar(&e.traceHAR, "trace-har", "", "write traced requests to this HAR file")
+	rootCmd.PersistentFlags().StringVar(&e.recordPath, "record", "", "save every request to the modules and their responses to this session file (secrets redacted)")
+	rootCmd.PersistentFlags().StringVar(&e.replayPath, "replay", "", "answer requests from this recorded session file instead of the modules")
+	rootCmd.PersistentFlags().IntVarP(&e.requestTimeout, "timeout", "t", 10, "set the timeout for requests in seconds")
+	rootCmd.PersistentFlags().IntVar(&e.connectTimeout, "connect-timeout", 3, "set the timeout for connecting to a module in seconds")
+	rootCmd.PersistentFlags().IntVar(&e.responseTimeout, "response-timeout", 10, "set the timeout for a module to start responding in seconds")
+	rootCmd.PersistentFlags().Int("retries", 3, "set how many times requests that only read are attempted")
+	rootCmd.PersistentFlags().BoolVar(&e.noUpdateCheck, "no-update-check", false, "don't check whether the modules are up to date")
+	rootCmd.PersistentFlags().BoolVar(&e.noRetry, "no-retry", false, "send every request once and never skip failing modules")
+	rootCmd.PersistentFlags().BoolVar(&e.waitLock, "wait-lock", false, "wait for another gladius command changing the node to finish instead of failing")
+	config.BindFlag("Retry.Attempts", rootCmd.PersistentFlags().Lookup("retries"))
+	config.BindFlag("Log.Level", rootCmd.PersistentFlags().Lookup("level"))
+	e.flags = rootCmd.PersistentFlags()
+
+	return rootCmd
+}
+
+// requires - annotation listing the module endpoints a command calls, see
+// node.Endpoints
+func requires(paths ...string) map[string]string {
+	return map[string]string{"endpoints": strings.Join(paths, ",")}
+}
+
+// locked - hold the lock in the Gladius base dir while cmd runs, for
+// commands changing the node. Commands that are done changing it but keep
+// running call e.releaseLock.
+func (e *env) locked(cmd *cobra.Command) *cobra.Command {
+	run := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		release, err := utils.AcquireLock(e.ctx, cmd.Name(), e.waitLock)
+		if err != nil {
+			return err
+		}
+		e.releaseLock = release
+		defer func() {
+			release()
+			e.releaseLock = func() {}
+		}()
+
+		return run(cmd, args)
+	}
+	return cmd
 }
 
 // Execute - call this to "activate" commands
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the first Ctrl-C cancels the requests in flight, a second one kills
+	// the CLI as usual
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		signal.Stop(interrupt)
+		log.Warning("Interrupted, cancelling requests")
+		cancel()
+	}()
+
+	if err := NewRootCommand(Options{Context: ctx}).Execute(); err != nil {
+		utils.PrintError(err)
 	}
 }
+
+func init() {
+	surveyCore.QuestionIcon = "[Gladius]"
+}
@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gladiusio/gladius-cli/utils"
+	"github.com/mgutz/ansi"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files with the current output")
+
+func init() {
+	ansi.DisableColors(true)
+}
+
+// fakeClient - answers requests by method and path, whatever the module
+type fakeClient struct {
+	responses map[string]string // "GET /version" -> body
+
+	mu       sync.Mutex
+	requests []string
+}
+
+func (c *fakeClient) Do(req *http.Request) (*http.Response, error) {
+	key := req.Method + " " + req.URL.Path
+
+	c.mu.Lock()
+	c.requests = append(c.requests, key)
+	c.mu.Unlock()
+
+	body, ok := c.responses[key]
+	status := http.StatusOK
+	if !ok {
+		status = http.StatusNotFound
+		body = `{"success": false, "error": "not found"}`
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func newFakeClient(version string) *fakeClient {
+	return &fakeClient{responses: map[string]string{
+		"GET /version":                    `{"success": true, "response": {"version": "` + version + `"}}`,
+		"POST /api/keystore/account/open": `{"success": true, "message": "Account opened"}`,
+		"GET /api/keystore/account":       `{"success": true, "response": {"address": "0xddF3711D905EC9FE3109b9EA802ca9d474C481eA"}}`,
+		"POST /service/set_state/all":     `{"success": true, "message": "Started"}`,
+		"POST /service/set_timeout":       `{"success": true, "message": "Timeout set"}`,
+	}}
+}
+
+// run - runs gladius with args in a fresh base dir, answering the questions
+// from in, and returns what it printed. A nil client sends the requests to
+// the modules on the configured ports.
+func run(t *testing.T, client utils.Doer, in string, args ...string) (string, error) {
+	var out bytes.Buffer
+	root := NewRootCommand(Options{
+		In:     strings.NewReader(in),
+		Out:    &out,
+		Err:    &out,
+		Client: client,
+		Clock:  func() time.Time { return time.Date(2018, 8, 20, 18, 0, 0, 0, time.UTC) },
+	})
+	root.SetArgs(append([]string{"--base-dir", t.TempDir(), "--log-file", "stderr", "--level", "error", "--no-update-check"}, args...))
+	err := root.Execute()
+	return out.String(), err
+}
+
+// golden - compares output with testdata/name.golden, rewritten with -update
+func golden(t *testing.T, name, output string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		err := ioutil.WriteFile(path, []byte(output), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != string(want) {
+		t.Errorf("output of %s differs from %s:\n%s", name, path, output)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		in      string
+		args    []string
+	}{
+		{name: "welcome"},
+		{name: "version-check", version: "0.8.0", args: []string{"version", "--check"}},
+		{name: "version-check-old", version: "0.6.2", args: []string{"version", "--check"}},
+		{name: "unlock", version: "0.8.0", in: "password\n", args: []string{"unlock"}},
+		{name: "pools-alias", args: []string{"pools", "alias", "list"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output, err := run(t, newFakeClient(test.version), test.in, test.args...)
+			if err != nil {
+				output += "error: " + err.Error() + "\n"
+			}
+			golden(t, test.name, output)
+		})
+	}
+}
+
+// every tree sends its requests through its own client
+func TestTreesKeepTheirClient(t *testing.T) {
+	first, second := newFakeClient("0.8.0"), newFakeClient("0.8.0")
+
+	_, err := run(t, first, "", "version", "--check")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = run(t, second, "password\n", "unlock")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, request := range first.requests {
+		if request != "GET /version" {
+			t.Errorf("the first tree's client was sent %s", request)
+		}
+	}
+	opened := false
+	for _, request := range second.requests {
+		opened = opened || request == "POST /api/keystore/account/open"
+	}
+	if !opened {
+		t.Errorf("the second tree's client wasn't sent the unlock, got %v", second.requests)
+	}
+}
@@ -0,0 +1,170 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gladiusio/gladius-cli/node"
+	"github.com/gladiusio/gladius-cli/utils"
+	"github.com/mgutz/ansi"
+	"github.com/spf13/cobra"
+)
+
+// txPollInterval - how often tx wait asks for the status of the transaction
+const txPollInterval = 2 * time.Second
+
+// txCommand - commands following transactions on the blockchain
+func (e *env) txCommand() *cobra.Command {
+	var waitTimeout time.Duration
+	var waitConfirmations int64
+
+	cmdTx := &cobra.Command{
+		Use:   "tx",
+		Short: "Follow your transactions",
+		Long:  "See the status of the transactions sent by your node",
+	}
+
+	cmdTxStatus := &cobra.Command{
+		Annotations: requires("/api/status/tx/<hash>"),
+		Use:         "status <hash>",
+		Short:       "See the status of a transaction",
+		Long:        "Show whether a transaction was mined, its block number and how many confirmations it has",
+		Args:        cobra.ExactArgs(1),
+		RunE:        e.txStatus,
+	}
+
+	cmdTxWait := &cobra.Command{
+		Annotations: requires("/api/status/tx/<hash>"),
+		Use:         "wait <hash>",
+		Short:       "Wait for a transaction to be mined",
+		Long:        "Wait until a transaction is mined and has enough confirmations, Ctrl-C stops waiting",
+		Args:        cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return e.txWait(args[0], waitConfirmations, waitTimeout)
+		},
+	}
+	cmdTxWait.Flags().DurationVar(&waitTimeout, "timeout", 10*time.Minute, "stop waiting after this long")
+	cmdTxWait.Flags().Int64Var(&waitConfirmations, "confirmations", 1, "wait for this many confirmations")
+
+	cmdTx.AddCommand(cmdTxStatus)
+	cmdTx.AddCommand(cmdTxWait)
+
+	return cmdTx
+}
+
+// checkTxHash - make sure hash looks like a transaction hash
+func checkTxHash(hash string) error {
+	if !regexp.MustCompile("^0x[a-fA-F0-9]{64}$").MatchString(hash) {
+		return utils.HandleError(errors.New("invalid tx hash "+hash), "Please enter a valid transaction hash (0x followed by 64 hex digits)", "commands.checkTxHash")
+	}
+	return nil
+}
+
+func (e *env) txStatus(cmd *cobra.Command, args []string) error {
+	err := checkTxHash(args[0])
+	if err != nil {
+		return err
+	}
+	err = node.RequireGateway(e.ctx)
+	if err != nil {
+		return err
+	}
+
+	status, err := utils.GetTx(e.ctx, args[0])
+	if err != nil {
+		return err
+	}
+	e.printTxStatus(status)
+
+	e.checkUpdate()
+	return nil
+}
+
+func (e *env) txWait(hash string, confirmations int64, timeout time.Duration) error {
+	err := checkTxHash(hash)
+	if err != nil {
+		return err
+	}
+	err = node.RequireGateway(e.ctx)
+	if err != nil {
+		return err
+	}
+
+	stopWatching := e.watchConfig()
+	defer stopWatching()
+
+	ctx, cancel := context.WithTimeout(e.ctx, timeout)
+	defer cancel()
+
+	// show the progress on one line, only when someone is watching
+	progress := func(status utils.TxStatus) {
+		if e.interactive {
+			fmt.Fprintf(e.stderr, "\rTx: %s\t Status: %s   ", hash, txState(status))
+		}
+	}
+
+	status, err := utils.WaitForTx(ctx, hash, confirmations, txPollInterval, progress)
+	if e.interactive {
+		fmt.Fprintln(e.stderr)
+	}
+	if err != nil {
+		switch {
+		case e.ctx.Err() != nil:
+			return utils.HandleError(err, "Stopped waiting, the transaction may still be mined. Check it with `gladius tx status "+hash+"`", "commands.txWait")
+		case ctx.Err() != nil:
+			return utils.HandleError(err, "Timed out after "+timeout.String()+", the transaction may still be mined. Check it with `gladius tx status "+hash+"`", "commands.txWait")
+		}
+		return err
+	}
+
+	e.printTxStatus(status)
+	if status.Failed {
+		return utils.HandleError(errors.New("transaction failed"), "The transaction was mined but failed", "commands.txWait")
+	}
+
+	return nil
+}
+
+// txState - pending, mined or failed
+func txState(status utils.TxStatus) string {
+	switch {
+	case status.Failed:
+		return "Failed"
+	case status.Complete:
+		return "Mined"
+	default:
+		return "Pending"
+	}
+}
+
+func (e *env) printTxStatus(status utils.TxStatus) {
+	stateColor := "83+hb"
+	if status.Failed {
+		stateColor = "196+hb"
+	} else if !status.Complete {
+		stateColor = "214+hb"
+	}
+
+	block := "-"
+	if status.BlockNumber > 0 {
+		block = strconv.FormatInt(status.BlockNumber, 10)
+	}
+
+	fmt.Fprintln(e.stdout, ansi.Color("TX:", "83+hb"), ansi.Color(status.Hash, "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("STATUS:", "83+hb"), ansi.Color(txState(status), stateColor))
+	fmt.Fprintln(e.stdout, ansi.Color("BLOCK:", "83+hb"), ansi.Color(block, "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("CONFIRMATIONS:", "83+hb"), ansi.Color(strconv.FormatInt(status.Confirmations, 10), "255+hb"))
+}
+
+// printTx - tell the user how to follow a transaction a command submitted
+func (e *env) printTx(hash string) {
+	if hash == "" {
+		return
+	}
+	fmt.Fprintln(e.stdout, ansi.Color("Tx:", "83+hb"), ansi.Color(hash, "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("Use", "255+hb"), ansi.Color("gladius tx wait "+hash, "83+hb"), ansi.Color("to wait for it to be mined", "255+hb"))
+}
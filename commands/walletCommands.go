@@ -0,0 +1,303 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/gladiusio/gladius-cli/keystore"
+	"github.com/gladiusio/gladius-cli/node"
+	"github.com/gladiusio/gladius-cli/utils"
+	"github.com/mgutz/ansi"
+	"github.com/spf13/cobra"
+	survey "gopkg.in/AlecAivazis/survey.v1"
+)
+
+// walletCommands - the commands for the node's account
+func (e *env) walletCommands() []*cobra.Command {
+	var balanceJSON bool
+	var signFile, verifyFile string
+
+	cmdBalance := &cobra.Command{
+		Annotations: requires("/api/keystore/account", "/api/account/<address>/balance/<symbol>"),
+		Use:         "balance [address]",
+		Short:       "See how much ETH and GLA you have",
+		Long:        "Show the ETH and GLA balances of your node's account, or of the address given",
+		Args:        cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return e.balance(args, balanceJSON)
+		},
+	}
+	cmdBalance.Flags().BoolVar(&balanceJSON, "json", false, "print the balances as JSON")
+
+	cmdWallet := &cobra.Command{
+		Use:   "wallet",
+		Short: "Use your node's wallet",
+		Long:  "Send funds and sign messages with the account of your node",
+	}
+
+	cmdTransfer := &cobra.Command{
+		Annotations: requires("/api/keystore/account", "/api/account/<address>/balance/<symbol>", "/api/keystore/transaction/estimate", "/api/keystore/transaction/send", "/api/status/tx/<hash>"),
+		Use:         "transfer <amount> <ETH|GLA> <address>",
+		Short:       "Send ETH or GLA to another address",
+		Long:        "Send ETH or GLA from your node's account, after showing the gas it costs and asking you to confirm",
+		Example:     "  gladius wallet transfer 1.5 GLA 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		Args:        cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return e.transfer(args[0], args[1], args[2])
+		},
+	}
+	e.locked(cmdTransfer)
+
+	cmdSign := &cobra.Command{
+		Annotations: requires("/api/keystore/account", "/api/keystore/account/sign"),
+		Use:         "sign [message]",
+		Short:       "Sign a message with your node's account",
+		Long:        "Sign a message, or the content of a file, with your node's account to prove that you control its address. The signature is an Ethereum personal-sign signature",
+		Args:        cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return e.sign(args, signFile)
+		},
+	}
+	cmdSign.Flags().StringVar(&signFile, "file", "", "sign the content of this file")
+
+	cmdVerify := &cobra.Command{
+		Use:   "verify <address> <signature> [message]",
+		Short: "Check a signed message",
+		Long:  "Check that a message, or the content of a file, was signed by an address. This works offline, the signer is recovered from the signature",
+		Args:  cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return e.verify(args, verifyFile)
+		},
+	}
+	cmdVerify.Flags().StringVar(&verifyFile, "file", "", "check the signature of the content of this file")
+
+	cmdWallet.AddCommand(cmdTransfer)
+	cmdWallet.AddCommand(cmdSign)
+	cmdWallet.AddCommand(cmdVerify)
+
+	return []*cobra.Command{cmdBalance, cmdWallet}
+}
+
+// balanceOutput - printed by balance --json
+type balanceOutput struct {
+	Address  string         `json:"address"`
+	Balances []balanceEntry `json:"balances"`
+}
+
+type balanceEntry struct {
+	Symbol   string `json:"symbol"`
+	Amount   string `json:"amount"`   // whole tokens
+	Base     string `json:"base"`     // smallest unit of the token (wei for ETH)
+	Decimals int    `json:"decimals"` // digits of the smallest unit
+}
+
+func (e *env) balance(args []string, asJSON bool) error {
+	err := node.RequireGateway(e.ctx)
+	if err != nil {
+		return err
+	}
+
+	var address string
+	if len(args) > 0 {
+		parsed, err := e.parseAddress(args[0], "commands.balance")
+		if err != nil {
+			return err
+		}
+		address = parsed.String()
+	} else {
+		address, err = keystore.GetAccounts(e.ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	balances, err := node.GetBalances(e.ctx, address)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		out := balanceOutput{Address: address}
+		for _, balance := range balances {
+			out.Balances = append(out.Balances, balanceEntry{
+				Symbol:   balance.Token.Symbol,
+				Amount:   balance.String(),
+				Base:     balance.Amount.String(),
+				Decimals: balance.Token.Decimals,
+			})
+		}
+		b, _ := json.MarshalIndent(out, "", "  ")
+		fmt.Fprintln(e.stdout, string(b))
+		return nil
+	}
+
+	fmt.Fprintln(e.stdout, ansi.Color("Account Address:", "83+hb"), ansi.Color(address, "255+hb"))
+	for _, balance := range balances {
+		fmt.Fprintln(e.stdout, ansi.Color(balance.Token.Symbol+":", "83+hb"), ansi.Color(balance.String(), "255+hb"))
+	}
+
+	e.checkUpdate()
+	return nil
+}
+
+func (e *env) transfer(amountArg, symbol, to string) error {
+	token, ok := node.FindToken(symbol)
+	if !ok {
+		return utils.HandleError(errors.New("unknown token "+symbol), "You can send ETH or GLA", "commands.transfer")
+	}
+	amount, err := utils.ParseUnits(amountArg, token.Decimals)
+	if err != nil || amount.Sign() <= 0 {
+		return utils.HandleError(errors.New("invalid amount "+amountArg), fmt.Sprintf("Please enter an amount above 0 with at most %d decimals", token.Decimals), "commands.transfer")
+	}
+	recipient, err := e.parseAddress(to, "commands.transfer")
+	if err != nil {
+		return err
+	}
+	checksummed := recipient.String()
+
+	err = node.RequireGateway(e.ctx)
+	if err != nil {
+		return err
+	}
+
+	from, err := keystore.GetAccounts(e.ctx)
+	if err != nil {
+		return err
+	}
+	balances, err := node.GetBalances(e.ctx, from)
+	if err != nil {
+		return err
+	}
+	estimate, err := node.EstimateTransfer(e.ctx, checksummed, token, amount)
+	if err != nil {
+		return err
+	}
+
+	// what is left after the transfer, the gas is always paid in ETH
+	remaining := make([]node.Balance, len(balances))
+	for i, balance := range balances {
+		left := new(big.Int).Set(balance.Amount)
+		if balance.Token.Symbol == token.Symbol {
+			left.Sub(left, amount)
+		}
+		if balance.Token.Symbol == "ETH" {
+			left.Sub(left, estimate.Fee())
+		}
+		if left.Sign() < 0 {
+			return utils.HandleError(errors.New("insufficient "+balance.Token.Symbol), fmt.Sprintf("Insufficient funds, you have %s %s", balance.String(), balance.Token.Symbol), "commands.transfer")
+		}
+		remaining[i] = node.Balance{Token: balance.Token, Amount: left}
+	}
+
+	shown := utils.FormatUnits(amount, token.Decimals) + " " + token.Symbol
+	fee := fmt.Sprintf("%s gas at %s gwei, at most %s ETH", estimate.Gas, utils.FormatUnits(estimate.GasPrice, 9), utils.FormatUnits(estimate.Fee(), 18))
+
+	fmt.Fprintln(e.stdout, ansi.Color("AMOUNT:", "83+hb"), ansi.Color(shown, "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("FROM:", "83+hb"), ansi.Color(utils.ChecksumAddress(from), "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("TO:", "83+hb"), ansi.Color(checksummed, "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("GAS:", "83+hb"), ansi.Color(fee, "255+hb"))
+	for _, balance := range remaining {
+		fmt.Fprintln(e.stdout, ansi.Color("REMAINING "+balance.Token.Symbol+":", "83+hb"), ansi.Color(balance.String(), "255+hb"))
+	}
+
+	// typing the amount back catches a misplaced decimal point, which a
+	// yes/no question doesn't
+	var typed string
+	err = e.prompter.AskOne(&survey.Input{Message: "Type " + shown + " to send it:"}, &typed, nil)
+	if err != nil {
+		return utils.HandleError(err, "Transfer cancelled", "commands.transfer")
+	}
+	if !strings.EqualFold(strings.Join(strings.Fields(typed), " "), shown) {
+		return utils.HandleError(errors.New("confirmation mismatch"), "That doesn't match, nothing was sent", "commands.transfer")
+	}
+
+	hash, err := node.Transfer(e.ctx, checksummed, token, amount)
+	if err != nil {
+		return err
+	}
+	e.printTx(hash)
+
+	// the transaction is sent, other commands don't need to wait for it to
+	// be mined
+	e.releaseLock()
+
+	return e.txWait(hash, 1, 10*time.Minute)
+}
+
+// readMessage - the message given as an argument, or the content of file
+func readMessage(args []string, file, path string) ([]byte, error) {
+	switch {
+	case file != "" && len(args) > 0:
+		return nil, utils.HandleError(errors.New("message and --file"), "Give either a message or --file, not both", path)
+	case file != "":
+		message, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, utils.HandleError(err, "Could not read "+file, path)
+		}
+		return message, nil
+	case len(args) > 0:
+		return []byte(args[0]), nil
+	}
+	return nil, utils.HandleError(errors.New("no message"), "Give the message to sign, or --file", path)
+}
+
+func (e *env) sign(args []string, file string) error {
+	message, err := readMessage(args, file, "commands.sign")
+	if err != nil {
+		return err
+	}
+
+	err = node.RequireGateway(e.ctx)
+	if err != nil {
+		return err
+	}
+	address, err := keystore.GetAccounts(e.ctx)
+	if err != nil {
+		return err
+	}
+
+	signature, err := keystore.SignMessage(e.ctx, message)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(e.stdout, ansi.Color("ADDRESS:", "83+hb"), ansi.Color(utils.ChecksumAddress(address), "255+hb"))
+	fmt.Fprintln(e.stdout, ansi.Color("SIGNATURE:", "83+hb"), ansi.Color(signature, "255+hb"))
+
+	e.checkUpdate()
+	return nil
+}
+
+// verify - recover the signer locally, nothing is sent to the modules
+func (e *env) verify(args []string, file string) error {
+	address, err := e.parseAddress(args[0], "commands.verify")
+	if err != nil {
+		return err
+	}
+	signature, err := keystore.ParseSignature(args[1])
+	if err != nil {
+		return utils.HandleError(err, "Please enter a valid signature, 0x followed by 130 hex digits", "commands.verify")
+	}
+	message, err := readMessage(args[2:], file, "commands.verify")
+	if err != nil {
+		return err
+	}
+
+	signer, err := keystore.RecoverAddress(message, signature)
+	if err != nil {
+		return utils.HandleError(err, "Invalid signature", "commands.verify")
+	}
+
+	fmt.Fprintln(e.stdout, ansi.Color("SIGNER:", "83+hb"), ansi.Color(utils.ChecksumAddress(signer), "255+hb"))
+	if !strings.EqualFold(signer, address.String()) {
+		return utils.HandleError(errors.New("signed by "+signer), "The message was not signed by "+address.String(), "commands.verify")
+	}
+	fmt.Fprintln(e.stdout, ansi.Color("VALID:", "83+hb"), ansi.Color("The message was signed by "+address.String(), "255+hb"))
+
+	return nil
+}
@@ -1,89 +1,194 @@
 package config
 
 import (
-	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-	"runtime"
-	"strings"
+	"sync"
 
-	"github.com/fsnotify/fsnotify"
 	log "github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
+// mu - guards the settings, which Watch replaces while commands read them.
+// Settings are read through this package for that reason.
+var mu sync.RWMutex
+
 // GetString - Wrapper around viper GetString
 func GetString(key string) string {
+	mu.RLock()
+	defer mu.RUnlock()
 	return viper.GetString(key)
 }
 
-// SetupConfig - Sets up, watches, and registers default config
-func SetupConfig(configName string, defaults map[string]string) {
+// GetBool - Wrapper around viper GetBool
+func GetBool(key string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return viper.GetBool(key)
+}
+
+// GetInt - Wrapper around viper GetInt
+func GetInt(key string) int {
+	mu.RLock()
+	defer mu.RUnlock()
+	return viper.GetInt(key)
+}
+
+// GetStringSlice - Wrapper around viper GetStringSlice
+func GetStringSlice(key string) []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return viper.GetStringSlice(key)
+}
+
+// BindFlag - Use the flag's value for key when it is set on the command line
+func BindFlag(key string, flag *pflag.Flag) error {
+	mu.Lock()
+	defer mu.Unlock()
+	return viper.BindPFlag(key, flag)
+}
+
+// SetupConfig - Sets up and registers default config. The file is looked for
+// in the current directory and then the config directory. Long-running
+// commands can follow changes to the file with Watch.
+func SetupConfig(configName string, defaults map[string]string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
 	viper.SetConfigName(configName)
+	name = configName
 
-	base, err := GetGladiusBase()
-	if err != nil {
-		viper.AddConfigPath(".") // Search only for local config
-	} else {
-		viper.AddConfigPath(".")
-		viper.AddConfigPath(base) // OS specifc
+	dirs := []string{"."}
+	paths, err := GetPaths()
+	if err == nil { // otherwise search only for local config
+		_, err = migrateLegacyBase(configName, paths)
+		if err != nil {
+			log.WithFields(log.Fields{"file": "config.go", "func": "SetupConfig"}).Warning("Could not move the config out of ~/.gladius: ", err)
+		}
+		dirs = append(dirs, paths.Config)
 	}
 
 	for key, value := range defaults {
 		viper.SetDefault(key, value)
 	}
 
+	file := findConfig(configName, dirs...)
+	if file == "" {
+		return nil
+	}
+	viper.SetConfigFile(file)
+
 	err = viper.ReadInConfig() // Find and read the config file
-	// Should probably fix this...
 	if err != nil {
-		if strings.HasPrefix(err.Error(), "Config File") {
-		} else { // Handle errors reading the config file
-			panic(fmt.Errorf("Fatal error config file: %s", err))
-		}
-	} else {
-		viper.WatchConfig()
-		viper.OnConfigChange(func(e fsnotify.Event) {
-			fmt.Println("Config file changed:", e.Name)
-		})
+		return fmt.Errorf("Fatal error config file: %s", err)
 	}
 
-}
-
-// GetGladiusBase - Returns the base directory
-func GetGladiusBase() (string, error) {
-	var m string
-	var err error
-
-	if os.Getenv("GLADIUSBASE") != "" {
-		m = os.Getenv("GLADIUSBASE")
-	} else {
-		switch runtime.GOOS {
-		case "windows":
-			m = filepath.Join(os.Getenv("HOMEDRIVE"), os.Getenv("HOMEPATH"), ".gladius")
-		case "linux":
-			m = os.Getenv("HOME") + "/.gladius"
-		case "darwin":
-			m = os.Getenv("HOME") + "/.gladius"
-		default:
-			m = ""
-			err = errors.New("unknown operating system, can't find gladius base directory. Set the GLADIUSBASE environment variable, or supply the directory as the first argument to add it manually")
-		}
+	migrated, err := Migrate(file)
+	if err != nil {
+		log.WithFields(log.Fields{"file": file}).Warning("Could not migrate config: ", err)
+	} else if migrated {
+		viper.ReadInConfig()
 	}
+	warnUnknownKeys(file)
 
-	return m, err
+	return nil
 }
 
+// CLIDefaults - defaults of the CLI settings
 func CLIDefaults() map[string]string {
+	mu.Lock()
+	defer mu.Unlock()
+	setDefaults(viper.GetViper())
+	return stringDefaults()
+}
+
+// stringDefaults - the defaults CLIDefaults returns, which depend on the
+// base dir
+func stringDefaults() map[string]string {
 	m := make(map[string]string)
 	base, err := GetGladiusBase()
 	if err != nil {
 		log.Fatal("Could not retrieve gladius base")
 	}
 	m["DirLogs"] = filepath.Join(base, "logs")
-	viper.SetDefault("Ports.Guardian", 7791)
-	viper.SetDefault("Ports.EdgeD", 8081)
-	viper.SetDefault("Ports.NetworkGateway", 3001)
 
 	return m
 }
+
+// setDefaults - defaults that aren't plain strings
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("SchemaVersion", CurrentSchemaVersion)
+	v.SetDefault("Ports.Guardian", 7791)
+	v.SetDefault("Ports.EdgeD", 8081)
+	v.SetDefault("Ports.NetworkGateway", 3001)
+	v.SetDefault("Log.Level", "info")
+	v.SetDefault("Log.MaxSizeMB", 10)
+	v.SetDefault("Log.RotateDays", 7)
+	v.SetDefault("Log.MaxAgeDays", 30)
+	v.SetDefault("Log.MaxBackups", 5)
+	v.SetDefault("Log.Compress", true)
+	v.SetDefault("Retry.Attempts", 3)
+	v.SetDefault("Retry.BaseDelayMS", 200)
+	v.SetDefault("Retry.MaxDelayMS", 2000)
+	v.SetDefault("Retry.BreakerThreshold", 3)
+	v.SetDefault("Retry.BreakerCooldownSeconds", 30)
+	v.SetDefault("UpdateCheck.Disabled", false)
+	v.SetDefault("UpdateCheck.CacheHours", 24)
+	v.SetDefault("Trace.RedactFields", []string{"passphrase", "password", "privateKey", "private_key", "email"})
+}
+
+// ConfigFileUsed - the config file that was read, "" when running on the
+// defaults
+func ConfigFileUsed() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return viper.ConfigFileUsed()
+}
+
+// name - base name of the config file, set by SetupConfig
+var name = "gladius-cli"
+
+// Edit - change the settings of the config file and write it back, for
+// settings the CLI manages itself. The file is created in the config dir
+// when there is none yet. Keys in settings are lower case.
+func Edit(edit func(settings map[string]interface{})) error {
+	file := ConfigFileUsed()
+	settings := make(map[string]interface{})
+	if file != "" {
+		v := viper.New()
+		v.SetConfigFile(file)
+		err := v.ReadInConfig()
+		if err != nil {
+			return err
+		}
+		settings = v.AllSettings()
+	} else {
+		dir, err := GetConfigDir()
+		if err != nil {
+			return err
+		}
+		err = os.MkdirAll(dir, 0755)
+		if err != nil {
+			return err
+		}
+		file = filepath.Join(dir, name+".toml")
+		settings["schemaversion"] = CurrentSchemaVersion
+	}
+
+	edit(settings)
+
+	out := viper.New()
+	setAll(out, "", settings)
+	err := out.WriteConfigAs(file)
+	if err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{"file": "config.go", "func": "Edit", "config": file}).Debug("Config written")
+	mu.Lock()
+	defer mu.Unlock()
+	viper.SetConfigFile(file)
+	return viper.ReadInConfig()
+}
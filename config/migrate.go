@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+)
+
+// migration - upgrades the raw settings of a config file from one schema
+// version to the next
+type migration struct {
+	from        int
+	description string
+	apply       func(settings map[string]interface{}) error
+}
+
+// migrations - registry of every migration, ordered by version
+var migrations []migration
+
+func registerMigration(from int, description string, apply func(settings map[string]interface{}) error) {
+	migrations = append(migrations, migration{from: from, description: description, apply: apply})
+}
+
+func init() {
+	// version 0 is every config written before the schema was versioned,
+	// the layout itself did not change
+	registerMigration(0, "add schemaVersion", func(settings map[string]interface{}) error {
+		return nil
+	})
+}
+
+// Migrate - upgrade the config file to CurrentSchemaVersion in place. The
+// original file is kept next to it as a backup. Returns true if the file was
+// changed.
+func Migrate(file string) (bool, error) {
+	v := viper.New()
+	v.SetConfigFile(file)
+	err := v.ReadInConfig()
+	if err != nil {
+		return false, err
+	}
+
+	version := cast.ToInt(v.Get("schemaVersion"))
+	if version > CurrentSchemaVersion {
+		log.WithFields(log.Fields{"file": file, "version": version}).Warning("Config was written by a newer version of the CLI, some settings may be ignored")
+		return false, nil
+	}
+	if version == CurrentSchemaVersion {
+		return false, nil
+	}
+
+	settings := v.AllSettings()
+	for _, m := range migrations {
+		if m.from < version {
+			continue
+		}
+		log.WithFields(log.Fields{"file": file, "from": m.from, "to": m.from + 1}).Debug("Migrating config: ", m.description)
+		err = m.apply(settings)
+		if err != nil {
+			return false, fmt.Errorf("migrating config from version %d: %s", m.from, err)
+		}
+	}
+	settings["schemaversion"] = CurrentSchemaVersion
+
+	backup := fmt.Sprintf("%s.v%d.bak", file, version)
+	original, err := ioutil.ReadFile(file)
+	if err != nil {
+		return false, err
+	}
+	err = ioutil.WriteFile(backup, original, 0644)
+	if err != nil {
+		return false, fmt.Errorf("backing up config: %s", err)
+	}
+
+	out := viper.New()
+	setAll(out, "", settings)
+	err = out.WriteConfigAs(file)
+	if err != nil {
+		return false, err
+	}
+
+	log.WithFields(log.Fields{"file": file, "backup": backup}).Warning("Config upgraded to schema version ", CurrentSchemaVersion)
+	return true, nil
+}
+
+func setAll(v *viper.Viper, prefix string, settings map[string]interface{}) {
+	for key, value := range settings {
+		if nested, ok := value.(map[string]interface{}); ok {
+			setAll(v, prefix+key+".", nested)
+			continue
+		}
+		v.Set(prefix+key, value)
+	}
+}
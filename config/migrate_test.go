@@ -0,0 +1,123 @@
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spf13/viper"
+)
+
+// writeConfig - a config file in a temp dir, its path is returned
+func writeConfig(t *testing.T, content string) string {
+	file := filepath.Join(t.TempDir(), "gladius-cli.toml")
+	err := ioutil.WriteFile(file, []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func readConfig(t *testing.T, file string) *viper.Viper {
+	t.Helper()
+	v := viper.New()
+	v.SetConfigFile(file)
+	err := v.ReadInConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+// a config written before the schema was versioned
+const oldConfig = `DirLogs = "/var/log/gladius"
+
+[Ports]
+Guardian = 7000
+
+[Pools.Aliases]
+home = "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"
+`
+
+func TestMigrateOldSchema(t *testing.T) {
+	file := writeConfig(t, oldConfig)
+
+	migrated, err := Migrate(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !migrated {
+		t.Fatal("an unversioned config wasn't migrated")
+	}
+
+	v := readConfig(t, file)
+	if v.GetInt("SchemaVersion") != CurrentSchemaVersion {
+		t.Errorf("schema version %d, want %d", v.GetInt("SchemaVersion"), CurrentSchemaVersion)
+	}
+	if v.GetInt("Ports.Guardian") != 7000 || v.GetString("DirLogs") != "/var/log/gladius" || v.GetString("Pools.Aliases.home") == "" {
+		t.Errorf("settings lost by the migration: %v", v.AllSettings())
+	}
+
+	// the original is kept as it was
+	backup, err := ioutil.ReadFile(file + ".v0.bak")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(backup) != oldConfig {
+		t.Errorf("backup is\n%s\nwant\n%s", backup, oldConfig)
+	}
+
+	// nothing more to do the second time
+	migrated, err = Migrate(file)
+	if err != nil || migrated {
+		t.Errorf("migrating again = %v, %v, want nothing done", migrated, err)
+	}
+}
+
+func TestMigrateNewerSchema(t *testing.T) {
+	content := "SchemaVersion = 99\n"
+	file := writeConfig(t, content)
+	hook := test.NewGlobal()
+
+	migrated, err := Migrate(file)
+	if err != nil || migrated {
+		t.Fatalf("migrating a newer config = %v, %v, want it left alone", migrated, err)
+	}
+	if b, _ := ioutil.ReadFile(file); string(b) != content {
+		t.Errorf("a newer config was rewritten:\n%s", b)
+	}
+	if entry := hook.LastEntry(); entry == nil || entry.Level != log.WarnLevel {
+		t.Error("no warning about the newer config")
+	}
+}
+
+func TestUnknownKeys(t *testing.T) {
+	file := writeConfig(t, oldConfig+`
+[Prots]
+EdgeD = 8000
+
+[Log]
+MaxSizeMB = 5
+Colour = true
+`)
+
+	unknown := UnknownKeys(readConfig(t, file))
+	if want := []string{"log.colour", "prots.edged"}; !reflect.DeepEqual(unknown, want) {
+		t.Errorf("unknown keys %v, want %v", unknown, want)
+	}
+
+	hook := test.NewGlobal()
+	warnUnknownKeys(file)
+	var warned []string
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == log.WarnLevel {
+			warned = append(warned, entry.Data["key"].(string))
+		}
+	}
+	if !reflect.DeepEqual(warned, unknown) {
+		t.Errorf("warned about %v, want %v", warned, unknown)
+	}
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// Paths - where the CLI keeps its files
+type Paths struct {
+	Config string // config file
+	State  string // logs, caches and the lock file
+}
+
+// baseDir - set with --base-dir, takes precedence over GLADIUSBASE
+var baseDir string
+
+// SetBaseDir - keep the config and the state in dir, "" goes back to the
+// default directories
+func SetBaseDir(dir string) {
+	baseDir = dir
+}
+
+// GetPaths - the directories of the CLI. A base dir set with --base-dir or
+// GLADIUSBASE holds everything, otherwise Linux follows the XDG base
+// directory spec and the other systems use ~/.gladius.
+func GetPaths() (Paths, error) {
+	if baseDir != "" {
+		return Paths{Config: baseDir, State: baseDir}, nil
+	}
+	if base := os.Getenv("GLADIUSBASE"); base != "" {
+		return Paths{Config: base, State: base}, nil
+	}
+
+	switch runtime.GOOS {
+	case "windows", "darwin":
+		legacy := legacyBase()
+		return Paths{Config: legacy, State: legacy}, nil
+	case "linux":
+		return Paths{
+			Config: filepath.Join(xdgDir("XDG_CONFIG_HOME", ".config"), "gladius"),
+			State:  filepath.Join(xdgDir("XDG_STATE_HOME", filepath.Join(".local", "state")), "gladius"),
+		}, nil
+	default:
+		return Paths{}, errors.New("unknown operating system, can't find gladius base directory. Set the GLADIUSBASE environment variable or use the --base-dir flag")
+	}
+}
+
+// GetGladiusBase - Returns the base directory for logs and state
+func GetGladiusBase() (string, error) {
+	paths, err := GetPaths()
+	return paths.State, err
+}
+
+// GetConfigDir - Returns the directory of the config file
+func GetConfigDir() (string, error) {
+	paths, err := GetPaths()
+	return paths.Config, err
+}
+
+// legacyBase - the single directory used before the XDG directories
+func legacyBase() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("HOMEDRIVE"), os.Getenv("HOMEPATH"), ".gladius")
+	}
+	return filepath.Join(os.Getenv("HOME"), ".gladius")
+}
+
+// xdgDir - the directory in env, or fallback relative to the home directory
+func xdgDir(env, fallback string) string {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(os.Getenv("HOME"), fallback)
+}
+
+// findConfig - the config file named configName in dirs, first match wins
+func findConfig(configName string, dirs ...string) string {
+	for _, dir := range dirs {
+		for _, ext := range viper.SupportedExts {
+			file := filepath.Join(dir, configName+"."+ext)
+			if info, err := os.Stat(file); err == nil && !info.IsDir() {
+				return file
+			}
+		}
+	}
+	return ""
+}
+
+// defaultPaths - no base dir was set with --base-dir or GLADIUSBASE
+func defaultPaths() bool {
+	return baseDir == "" && os.Getenv("GLADIUSBASE") == ""
+}
+
+// migrateLegacyBase - move the files of the CLI out of ~/.gladius the first
+// time the XDG directories are used. The Gladius modules share ~/.gladius,
+// so only the config (and its backups) and the version cache are moved.
+// Nothing is moved into a base dir set with --base-dir or GLADIUSBASE, which
+// may only be used for a single command.
+func migrateLegacyBase(configName string, paths Paths) ([]string, error) {
+	legacy := legacyBase()
+	if !defaultPaths() || paths.Config == legacy || findConfig(configName, paths.Config) != "" {
+		return nil, nil
+	}
+
+	configs, err := filepath.Glob(filepath.Join(legacy, configName+".*"))
+	if err != nil || len(configs) == 0 {
+		return nil, err
+	}
+
+	moves := make(map[string]string)
+	for _, file := range configs {
+		moves[file] = filepath.Join(paths.Config, filepath.Base(file))
+	}
+	cache := filepath.Join(legacy, "version-cache.json")
+	if _, err := os.Stat(cache); err == nil {
+		moves[cache] = filepath.Join(paths.State, "version-cache.json")
+	}
+
+	var moved []string
+	for from, to := range moves {
+		err := moveFile(from, to)
+		if err != nil {
+			return moved, err
+		}
+		moved = append(moved, to)
+		log.WithFields(log.Fields{"file": "paths.go", "func": "migrateLegacyBase", "from": from, "to": to}).Info("Moved to the XDG directories")
+	}
+
+	return moved, nil
+}
+
+// moveFile - rename, or copy and remove when from and to are on different
+// file systems
+func moveFile(from, to string) error {
+	err := os.MkdirAll(filepath.Dir(to), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	if os.Rename(from, to) == nil {
+		return nil
+	}
+
+	in, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(to)
+		return err
+	}
+
+	return os.Remove(from)
+}
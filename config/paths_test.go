@@ -0,0 +1,116 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// legacyHome - a home directory with the config and version cache in
+// ~/.gladius, the XDG directories left to their defaults
+func legacyHome(t *testing.T) string {
+	if runtime.GOOS != "linux" {
+		t.Skip("the XDG directories are only used on Linux")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("GLADIUSBASE", "")
+	t.Cleanup(func() {
+		SetBaseDir("")
+		mu.Lock()
+		viper.Reset()
+		mu.Unlock()
+	})
+
+	legacy := filepath.Join(home, ".gladius")
+	for name, content := range map[string]string{
+		"gladius-cli.toml":   "[Ports]\nGuardian = 7000\n",
+		"version-cache.json": "{}",
+		"gladius-guardian":   "module",
+	} {
+		err := os.MkdirAll(legacy, 0755)
+		if err == nil {
+			err = ioutil.WriteFile(filepath.Join(legacy, name), []byte(content), 0644)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return home
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestMigrateLegacyBase(t *testing.T) {
+	home := legacyHome(t)
+
+	err := SetupConfig("gladius-cli", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	moved := map[string]bool{
+		filepath.Join(home, ".config", "gladius", "gladius-cli.toml"):           true,
+		filepath.Join(home, ".local", "state", "gladius", "version-cache.json"): true,
+		filepath.Join(home, ".gladius", "gladius-cli.toml"):                     false,
+		filepath.Join(home, ".gladius", "version-cache.json"):                   false,
+		filepath.Join(home, ".gladius", "gladius-guardian"):                     true,
+	}
+	for path, want := range moved {
+		if exists(path) != want {
+			t.Errorf("%s exists: %v, want %v", path, !want, want)
+		}
+	}
+	if port := GetInt("Ports.Guardian"); port != 7000 {
+		t.Errorf("port %d, want the moved config read", port)
+	}
+}
+
+// a base dir may be for a single command, the files in ~/.gladius stay put
+func TestNoMigrationWithBaseDir(t *testing.T) {
+	tests := []struct {
+		name string
+		set  func(t *testing.T, dir string)
+	}{
+		{"--base-dir", func(t *testing.T, dir string) { SetBaseDir(dir) }},
+		{"GLADIUSBASE", func(t *testing.T, dir string) { t.Setenv("GLADIUSBASE", dir) }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			home := legacyHome(t)
+			base := t.TempDir()
+			test.set(t, base)
+
+			err := SetupConfig("gladius-cli", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, name := range []string{"gladius-cli.toml", "version-cache.json"} {
+				if !exists(filepath.Join(home, ".gladius", name)) {
+					t.Errorf("%s was moved out of ~/.gladius", name)
+				}
+				if exists(filepath.Join(base, name)) {
+					t.Errorf("%s was moved into the base dir", name)
+				}
+			}
+			if exists(filepath.Join(home, ".config", "gladius")) || exists(filepath.Join(home, ".local", "state", "gladius")) {
+				t.Error("the XDG directories were created")
+			}
+			if viper.ConfigFileUsed() != "" {
+				t.Errorf("read %s, want no config in the base dir", viper.ConfigFileUsed())
+			}
+		})
+	}
+}
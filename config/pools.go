@@ -0,0 +1,97 @@
+package config
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// aliasPattern - alias names, no dots as they would nest the config key
+var aliasPattern = regexp.MustCompile("^[a-z][a-z0-9_-]*$")
+
+// PoolAliases - every pool alias, alias -> address
+func PoolAliases() map[string]string {
+	return viper.GetStringMapString("Pools.Aliases")
+}
+
+// PoolAlias - the address of the pool called name
+func PoolAlias(name string) (string, bool) {
+	address, ok := PoolAliases()[strings.ToLower(name)]
+	return address, ok
+}
+
+// SetPoolAlias - save name as an alias of the pool address in the config file
+func SetPoolAlias(name, address string) error {
+	name = strings.ToLower(name)
+	if !aliasPattern.MatchString(name) {
+		return errors.New("An alias starts with a letter and has only letters, digits, - and _")
+	}
+
+	return Edit(func(settings map[string]interface{}) {
+		pools, ok := settings["pools"].(map[string]interface{})
+		if !ok {
+			pools = make(map[string]interface{})
+			settings["pools"] = pools
+		}
+		aliases, ok := pools["aliases"].(map[string]interface{})
+		if !ok {
+			aliases = make(map[string]interface{})
+			pools["aliases"] = aliases
+		}
+		aliases[name] = address
+	})
+}
+
+// RemovePoolAlias - remove the alias name from the config file
+func RemovePoolAlias(name string) error {
+	name = strings.ToLower(name)
+	if _, ok := PoolAlias(name); !ok {
+		return errors.New("There is no pool alias " + name)
+	}
+
+	return Edit(func(settings map[string]interface{}) {
+		pools, _ := settings["pools"].(map[string]interface{})
+		aliases, _ := pools["aliases"].(map[string]interface{})
+		delete(aliases, name)
+	})
+}
+
+// lastPoolPath - file remembering the last pool applied to
+func lastPoolPath() (string, error) {
+	base, err := GetGladiusBase()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "last-pool"), nil
+}
+
+// LastPool - the last pool applied to, "" if there is none
+func LastPool() string {
+	path, err := lastPoolPath()
+	if err != nil {
+		return ""
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
+
+// SetLastPool - remember the pool applied to, so check can default to it
+func SetLastPool(address string) error {
+	path, err := lastPoolPath()
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, []byte(address+"\n"), 0644)
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// CurrentSchemaVersion - version of the config layout this CLI understands
+const CurrentSchemaVersion = 1
+
+// Config - typed view of the CLI config file
+type Config struct {
+	SchemaVersion int
+	DirLogs       string
+	Ports         Ports
+	Log           Log
+	Trace         Trace
+	Retry         Retry
+	UpdateCheck   UpdateCheck
+	Pools         Pools
+}
+
+// Pools - the pools the user works with
+type Pools struct {
+	Aliases map[string]string // alias (lower case) -> pool address
+}
+
+// UpdateCheck - the check for newer modules at the end of most commands
+type UpdateCheck struct {
+	Disabled   bool
+	CacheHours int // how long the official version list is cached
+}
+
+// Retry - retrying idempotent requests and skipping hosts that keep failing
+type Retry struct {
+	Attempts               int // total attempts for GET requests, 1 disables retrying
+	BaseDelayMS            int // delay before the first retry, doubled for every retry
+	MaxDelayMS             int // upper bound of the delay between retries
+	BreakerThreshold       int // consecutive failures before a host is skipped, 0 disables it
+	BreakerCooldownSeconds int // how long a failing host is skipped
+}
+
+// Trace - request tracing (--trace)
+type Trace struct {
+	RedactFields []string // fields of JSON and form bodies, query strings and headers whose values are never traced
+}
+
+// Log - level, rotation and retention of the CLI log file
+type Log struct {
+	Level      string // debug, info, warn or error, --level takes precedence
+	MaxSizeMB  int    // rotate the log once it grows past this size
+	RotateDays int    // rotate the log once it was started this long ago
+	MaxAgeDays int    // remove rotated logs older than this
+	MaxBackups int    // keep at most this many rotated logs
+	Compress   bool   // gzip rotated logs
+}
+
+// Ports - local ports of the Gladius modules
+type Ports struct {
+	Guardian       int
+	EdgeD          int
+	NetworkGateway int
+}
+
+// Module - a Gladius module the CLI talks to
+type Module struct {
+	Name    string // name used by the CLI (status, version, ...)
+	Release string // name used in the official version manifest
+	PortKey string // config key holding the module's port
+}
+
+// Modules - every module the CLI talks to, so the module names and their
+// config keys can't drift apart
+var Modules = []Module{
+	{Name: "guardian", Release: "gladius-guardian", PortKey: "Ports.Guardian"},
+	{Name: "edged", Release: "gladius-edged", PortKey: "Ports.EdgeD"},
+	{Name: "network-gateway", Release: "gladius-network-gateway", PortKey: "Ports.NetworkGateway"},
+}
+
+// Get - decode the current settings into a Config
+func Get() (Config, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var c Config
+	err := viper.Unmarshal(&c)
+	return c, err
+}
+
+// ModulePort - port of the named module
+func ModulePort(name string) (int, error) {
+	for _, m := range Modules {
+		if m.Name == name {
+			return GetInt(m.PortKey), nil
+		}
+	}
+
+	return 0, fmt.Errorf("Module %s not found", name)
+}
+
+// knownKeys - every (lower case) key path the Config struct declares.
+// Map fields accept any key below them.
+func knownKeys(t reflect.Type, prefix string, keys map[string]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key := strings.ToLower(prefix + f.Name)
+
+		switch f.Type.Kind() {
+		case reflect.Struct:
+			knownKeys(f.Type, key+".", keys)
+		case reflect.Map:
+			keys[key+".*"] = true
+		default:
+			keys[key] = true
+		}
+	}
+}
+
+// UnknownKeys - keys in the config file the CLI does not understand
+func UnknownKeys(v *viper.Viper) []string {
+	keys := make(map[string]bool)
+	knownKeys(reflect.TypeOf(Config{}), "", keys)
+
+	var unknown []string
+	for _, key := range v.AllKeys() {
+		if keys[key] || underMap(key, keys) {
+			continue
+		}
+		unknown = append(unknown, key)
+	}
+	sort.Strings(unknown)
+
+	return unknown
+}
+
+func underMap(key string, keys map[string]bool) bool {
+	for i := strings.LastIndex(key, "."); i > 0; i = strings.LastIndex(key[:i], ".") {
+		if keys[key[:i]+".*"] {
+			return true
+		}
+	}
+	return false
+}
+
+// warnUnknownKeys - warn about settings that will be ignored
+func warnUnknownKeys(file string) {
+	v := viper.New()
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		return
+	}
+
+	for _, key := range UnknownKeys(v) {
+		log.WithFields(log.Fields{"file": file, "key": key}).Warning("Unknown config key, it will be ignored")
+	}
+}
@@ -0,0 +1,219 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// Subscriber - called with the previous and the new config after the config
+// file changed. Returning an error rejects the new config.
+type Subscriber func(previous, next Config) error
+
+type subscription struct {
+	id int
+	fn Subscriber
+}
+
+var (
+	subscribersMu sync.Mutex
+	subscribers   []subscription
+	nextID        int
+)
+
+// Subscribe - call fn every time the config file changes. The returned
+// function removes the subscription.
+func Subscribe(fn Subscriber) func() {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+
+	nextID++
+	id := nextID
+	subscribers = append(subscribers, subscription{id: id, fn: fn})
+
+	return func() {
+		subscribersMu.Lock()
+		defer subscribersMu.Unlock()
+		for i, s := range subscribers {
+			if s.id == id {
+				subscribers = append(subscribers[:i], subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Validate - check a config for values the CLI can't work with
+func Validate(c Config) error {
+	if c.SchemaVersion > CurrentSchemaVersion {
+		return fmt.Errorf("schemaVersion %d is newer than this CLI supports (%d)", c.SchemaVersion, CurrentSchemaVersion)
+	}
+
+	ports := map[string]int{
+		"Ports.Guardian":       c.Ports.Guardian,
+		"Ports.EdgeD":          c.Ports.EdgeD,
+		"Ports.NetworkGateway": c.Ports.NetworkGateway,
+	}
+	for key, port := range ports {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("%s must be between 1 and 65535, got %d", key, port)
+		}
+	}
+
+	if c.DirLogs == "" {
+		return errors.New("DirLogs can't be empty")
+	}
+
+	if c.Retry.Attempts < 0 || c.Retry.BaseDelayMS < 0 || c.Retry.MaxDelayMS < 0 || c.Retry.BreakerThreshold < 0 || c.Retry.BreakerCooldownSeconds < 0 {
+		return errors.New("Retry settings can't be negative")
+	}
+
+	if c.Log.MaxSizeMB < 0 || c.Log.RotateDays < 0 || c.Log.MaxAgeDays < 0 || c.Log.MaxBackups < 0 {
+		return errors.New("Log settings can't be negative")
+	}
+
+	return nil
+}
+
+// Watch - reload the config when the file changes and notify subscribers.
+// A config that fails validation, or is rejected by a subscriber, is not
+// applied and every subscriber keeps the previous one. Only long-running
+// commands should call this, and call stop once done.
+func Watch() (stop func(), err error) {
+	file := ConfigFileUsed()
+	if file == "" {
+		return nil, errors.New("no config file in use")
+	}
+	file = filepath.Clean(file)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	// watch the directory to pick up editors that save by renaming
+	err = watcher.Add(filepath.Dir(file))
+	if err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		defer watcher.Close()
+		for {
+			select {
+			case <-done:
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != file || event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+				err := reload(file)
+				if err != nil {
+					log.WithFields(log.Fields{"file": file}).Error("Config change rejected, keeping previous config: ", err)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.WithFields(log.Fields{"file": file}).Warning("Config watcher: ", err)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+		})
+	}, nil
+}
+
+// reloadMu - one reload at a time, so subscribers see every change in order
+var reloadMu sync.Mutex
+
+// reload - validate the changed file and hand it to the subscribers, rolling
+// back the ones that already accepted it if a later one refuses. The active
+// settings are only replaced once every subscriber accepted them.
+func reload(file string) error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	configType := strings.TrimPrefix(filepath.Ext(file), ".")
+
+	// decode the new file on its own so a broken file never touches the
+	// active settings
+	candidate := viper.New()
+	for key, value := range stringDefaults() {
+		candidate.SetDefault(key, value)
+	}
+	setDefaults(candidate)
+	candidate.SetConfigType(configType)
+	err = candidate.ReadConfig(bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+
+	var next Config
+	err = candidate.Unmarshal(&next)
+	if err != nil {
+		return err
+	}
+	err = Validate(next)
+	if err != nil {
+		return err
+	}
+
+	previous, err := Get()
+	if err != nil {
+		return err
+	}
+
+	subscribersMu.Lock()
+	active := make([]subscription, len(subscribers))
+	copy(active, subscribers)
+	subscribersMu.Unlock()
+
+	for i, s := range active {
+		err = s.fn(previous, next)
+		if err != nil {
+			for _, accepted := range active[:i] {
+				accepted.fn(next, previous)
+			}
+			return err
+		}
+	}
+
+	mu.Lock()
+	err = viper.ReadConfig(bytes.NewReader(content))
+	mu.Unlock()
+	if err != nil {
+		for _, accepted := range active {
+			accepted.fn(next, previous)
+		}
+		return err
+	}
+
+	log.WithFields(log.Fields{"file": file}).Info("Config reloaded")
+	return nil
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// useConfig - make file the active config, as SetupConfig does
+func useConfig(t *testing.T, content string) string {
+	file := writeConfig(t, content)
+
+	mu.Lock()
+	viper.Reset()
+	setDefaults(viper.GetViper())
+	viper.SetConfigFile(file)
+	err := viper.ReadInConfig()
+	mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		mu.Lock()
+		viper.Reset()
+		mu.Unlock()
+	})
+	return file
+}
+
+func rewrite(t *testing.T, file, content string) {
+	err := ioutil.WriteFile(file, []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// change - a subscription recording the changes it is told about
+type change struct{ from, to int }
+
+func recordGuardian(t *testing.T, changes *[]change, err error) {
+	unsubscribe := Subscribe(func(previous, next Config) error {
+		*changes = append(*changes, change{previous.Ports.Guardian, next.Ports.Guardian})
+		return err
+	})
+	t.Cleanup(unsubscribe)
+}
+
+func TestReloadApplies(t *testing.T) {
+	file := useConfig(t, "[Ports]\nGuardian = 7000\n")
+	var changes []change
+	recordGuardian(t, &changes, nil)
+
+	rewrite(t, file, "[Ports]\nGuardian = 7001\n")
+	err := reload(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(changes) != 1 || changes[0] != (change{7000, 7001}) {
+		t.Errorf("subscriber told %v, want 7000 -> 7001", changes)
+	}
+	if port := GetInt("Ports.Guardian"); port != 7001 {
+		t.Errorf("port %d after reloading, want 7001", port)
+	}
+	// the defaults are still there
+	if GetInt("Ports.EdgeD") != 8081 {
+		t.Errorf("default EdgeD port lost: %d", GetInt("Ports.EdgeD"))
+	}
+}
+
+func TestReloadInvalid(t *testing.T) {
+	tests := []struct {
+		name, content string
+	}{
+		{"port out of range", "[Ports]\nGuardian = 70000\n"},
+		{"negative retries", "[Retry]\nAttempts = -1\n"},
+		{"newer schema", "SchemaVersion = 99\n"},
+		{"not toml", "[Ports\nGuardian = 7001\n"},
+	}
+
+	for _, test := range tests {
+		file := useConfig(t, "[Ports]\nGuardian = 7000\n")
+		var changes []change
+		unsubscribe := Subscribe(func(previous, next Config) error {
+			changes = append(changes, change{previous.Ports.Guardian, next.Ports.Guardian})
+			return nil
+		})
+
+		rewrite(t, file, test.content)
+		err := reload(file)
+		unsubscribe()
+
+		if err == nil {
+			t.Errorf("%s: reloaded", test.name)
+		}
+		if len(changes) != 0 {
+			t.Errorf("%s: subscriber told %v", test.name, changes)
+		}
+		if port := GetInt("Ports.Guardian"); port != 7000 {
+			t.Errorf("%s: port %d, want the previous 7000", test.name, port)
+		}
+	}
+}
+
+// a subscriber refusing the change rolls back the ones before it
+func TestReloadRollback(t *testing.T) {
+	file := useConfig(t, "[Ports]\nGuardian = 7000\n")
+	var accepted, refused []change
+	recordGuardian(t, &accepted, nil)
+	recordGuardian(t, &refused, errors.New("can't move"))
+
+	rewrite(t, file, "[Ports]\nGuardian = 7001\n")
+	err := reload(file)
+	if err == nil || err.Error() != "can't move" {
+		t.Fatalf("reload = %v, want the refusal", err)
+	}
+
+	if want := []change{{7000, 7001}, {7001, 7000}}; len(accepted) != 2 || accepted[0] != want[0] || accepted[1] != want[1] {
+		t.Errorf("accepting subscriber told %v, want %v", accepted, want)
+	}
+	if len(refused) != 1 {
+		t.Errorf("refusing subscriber told %v, want the change only", refused)
+	}
+	if port := GetInt("Ports.Guardian"); port != 7000 {
+		t.Errorf("port %d, want the previous 7000", port)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	file := useConfig(t, "[Ports]\nGuardian = 7000\n")
+	changed := make(chan change, 10)
+	defer Subscribe(func(previous, next Config) error {
+		changed <- change{previous.Ports.Guardian, next.Ports.Guardian}
+		return nil
+	})()
+
+	stop, err := Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	rewrite(t, file, "[Ports]\nGuardian = 7001\n")
+	select {
+	case c := <-changed:
+		if c.to != 7001 {
+			t.Errorf("told %v, want the change to 7001", c)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the change was never noticed")
+	}
+
+	// nothing is followed once stopped
+	stop()
+	stop()
+	for len(changed) > 0 {
+		<-changed
+	}
+	rewrite(t, file, "[Ports]\nGuardian = 7002\n")
+	select {
+	case c := <-changed:
+		t.Errorf("told %v after stopping", c)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -0,0 +1,95 @@
+// Package secp256k1 is the curve of Ethereum keys, just the arithmetic the
+// CLI needs to recover the signer of a message and the mock needs to sign
+// one. It is not constant time, so it is internal: nothing outside the CLI
+// should hold keys with it.
+package secp256k1
+
+import "math/big"
+
+// y² = x³ + 7 over the field of p, with generator (gx, gy) of order n
+var (
+	curveP, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+	curveN, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	curveGx, _ = new(big.Int).SetString("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", 16)
+	curveGy, _ = new(big.Int).SetString("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8", 16)
+)
+
+// Point - a point of the curve, nil coordinates for the point at infinity
+type Point struct {
+	X, Y *big.Int
+}
+
+// Order - n, the order of the generator
+func Order() *big.Int {
+	return new(big.Int).Set(curveN)
+}
+
+// Generator - G
+func Generator() Point {
+	return Point{new(big.Int).Set(curveGx), new(big.Int).Set(curveGy)}
+}
+
+// PointFromX - the point with x and an odd or even y, false when x is not
+// on the curve
+func PointFromX(x *big.Int, odd bool) (Point, bool) {
+	y := new(big.Int).Exp(x, big.NewInt(3), curveP)
+	y.Add(y, big.NewInt(7)).Mod(y, curveP)
+	if y.ModSqrt(y, curveP) == nil {
+		return Point{}, false
+	}
+	if (y.Bit(0) == 1) != odd {
+		y.Sub(curveP, y)
+	}
+	return Point{new(big.Int).Set(x), y}, true
+}
+
+// Infinity - a is the point at infinity
+func (a Point) Infinity() bool {
+	return a.X == nil
+}
+
+// Add - a + b
+func (a Point) Add(b Point) Point {
+	switch {
+	case a.Infinity():
+		return b
+	case b.Infinity():
+		return a
+	}
+
+	var slope *big.Int
+	if a.X.Cmp(b.X) == 0 {
+		sum := new(big.Int).Add(a.Y, b.Y)
+		if sum.Mod(sum, curveP).Sign() == 0 {
+			return Point{}
+		}
+		// tangent: 3x² / 2y
+		slope = new(big.Int).Mul(a.X, a.X)
+		slope.Mul(slope, big.NewInt(3))
+		slope.Mul(slope, new(big.Int).ModInverse(new(big.Int).Lsh(a.Y, 1), curveP))
+	} else {
+		dx := new(big.Int).Sub(b.X, a.X)
+		dx.Mod(dx, curveP)
+		slope = new(big.Int).Sub(b.Y, a.Y)
+		slope.Mul(slope, new(big.Int).ModInverse(dx, curveP))
+	}
+	slope.Mod(slope, curveP)
+
+	x := new(big.Int).Mul(slope, slope)
+	x.Sub(x, a.X).Sub(x, b.X).Mod(x, curveP)
+	y := new(big.Int).Sub(a.X, x)
+	y.Mul(y, slope).Sub(y, a.Y).Mod(y, curveP)
+	return Point{x, y}
+}
+
+// Mul - k·a, by double and add
+func (a Point) Mul(k *big.Int) Point {
+	result := Point{}
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		result = result.Add(result)
+		if k.Bit(i) == 1 {
+			result = result.Add(a)
+		}
+	}
+	return result
+}
@@ -0,0 +1,80 @@
+package secp256k1
+
+import (
+	"math/big"
+	"testing"
+)
+
+// 2G and 3G, from the test vectors of the curve
+const (
+	twoGx   = "c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5"
+	twoGy   = "1ae168fea63dc339a3c58419466ceaeef7f632653266d0e1236431a950cfe52a"
+	threeGx = "f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9"
+	threeGy = "388f7b0f632de8140fe337e62a37f3566500a99934c2231b6cb9fd7584b8e672"
+)
+
+func hexPoint(x, y string) Point {
+	px, _ := new(big.Int).SetString(x, 16)
+	py, _ := new(big.Int).SetString(y, 16)
+	return Point{px, py}
+}
+
+func equal(a, b Point) bool {
+	if a.Infinity() || b.Infinity() {
+		return a.Infinity() && b.Infinity()
+	}
+	return a.X.Cmp(b.X) == 0 && a.Y.Cmp(b.Y) == 0
+}
+
+func TestArithmetic(t *testing.T) {
+	g := Generator()
+	two := hexPoint(twoGx, twoGy)
+	three := hexPoint(threeGx, threeGy)
+
+	if !equal(g.Add(g), two) {
+		t.Error("G + G is not 2G")
+	}
+	if !equal(g.Add(two), three) || !equal(two.Add(g), three) {
+		t.Error("G + 2G is not 3G")
+	}
+	if !equal(g.Mul(big.NewInt(3)), three) {
+		t.Error("3·G is not 3G")
+	}
+	if !g.Mul(Order()).Infinity() {
+		t.Error("n·G is not the point at infinity")
+	}
+	if !equal(g.Mul(new(big.Int).Sub(Order(), big.NewInt(1))).Add(g), Point{}) {
+		t.Error("(n-1)·G + G is not the point at infinity")
+	}
+	if !equal(g.Add(Point{}), g) || !equal((Point{}).Add(g), g) {
+		t.Error("the point at infinity is not the identity")
+	}
+	if !(Point{}).Mul(big.NewInt(5)).Infinity() || !g.Mul(big.NewInt(0)).Infinity() {
+		t.Error("multiplying by or the point at infinity")
+	}
+
+	// the generator is a copy
+	g.X.SetInt64(1)
+	if Generator().X.Cmp(big.NewInt(1)) == 0 {
+		t.Error("Generator returned the curve's own coordinates")
+	}
+}
+
+func TestPointFromX(t *testing.T) {
+	three := hexPoint(threeGx, threeGy)
+	odd := three.Y.Bit(0) == 1
+
+	p, ok := PointFromX(three.X, odd)
+	if !ok || !equal(p, three) {
+		t.Errorf("PointFromX(3G.x, %v) = %v, %v, want 3G", odd, p, ok)
+	}
+	p, ok = PointFromX(three.X, !odd)
+	if !ok || p.Y.Cmp(three.Y) == 0 || !p.Add(three).Infinity() {
+		t.Errorf("PointFromX(3G.x, %v) = %v, %v, want -3G", !odd, p, ok)
+	}
+
+	// x³ + 7 = 132 has no square root modulo p
+	if _, ok := PointFromX(big.NewInt(5), false); ok {
+		t.Error("x = 5 is on the curve")
+	}
+}
@@ -1,18 +1,20 @@
 package keystore
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/gladiusio/gladius-cli/config"
 	"github.com/gladiusio/gladius-cli/utils"
 	log "github.com/sirupsen/logrus"
 )
 
 // CreatePGP - create a new pgp key and return path
-func CreatePGP(data interface{}) (string, error) {
-	url := "http://localhost:3001/api/keystore/pgp/create"
+func CreatePGP(ctx context.Context, data interface{}) (string, error) {
+	url := fmt.Sprintf("http://localhost:%d/api/keystore/pgp/create", config.GetInt("Ports.NetworkGateway"))
 
 	log.WithFields(log.Fields{"file": "pgp.go", "func": "CreatePGP"}).Debug("POST: ", url)
-	res, err := utils.SendRequest("POST", url, data)
+	res, err := utils.SendRequest(ctx, "POST", url, data)
 	if err != nil {
 		return "", utils.HandleError(err, "", "pgp.CreatePGP")
 	}
@@ -0,0 +1,101 @@
+package keystore
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/gladiusio/gladius-cli/internal/secp256k1"
+	"golang.org/x/crypto/sha3"
+)
+
+// keccak256 - the hash used by Ethereum
+func keccak256(data ...[]byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	for _, b := range data {
+		hash.Write(b)
+	}
+	return hash.Sum(nil)
+}
+
+// pad32 - n as a 32 byte big endian number
+func pad32(n *big.Int) []byte {
+	b := n.Bytes()
+	return append(make([]byte, 32-len(b)), b...)
+}
+
+// pointAddress - the address of a public key, the last 20 bytes of the hash
+// of its coordinates
+func pointAddress(pub secp256k1.Point) string {
+	return "0x" + hex.EncodeToString(keccak256(pad32(pub.X), pad32(pub.Y))[12:])
+}
+
+// HashMessage - the hash signed by personal-sign (eth_sign), which prefixes
+// the message so a signature can't be replayed as a transaction
+func HashMessage(message []byte) []byte {
+	return keccak256([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d", len(message))), message)
+}
+
+// ParseSignature - decode a hex signature of 65 bytes, r, s and v
+func ParseSignature(signature string) ([]byte, error) {
+	sig, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(signature, "0x"), "0X"))
+	if err != nil || len(sig) != 65 {
+		return nil, errors.New("a signature is 0x followed by 130 hex digits")
+	}
+	return sig, nil
+}
+
+// RecoverAddress - the address whose key made a personal-sign signature of
+// message. v is 27 or 28, or 0 or 1 as some wallets write it.
+func RecoverAddress(message, signature []byte) (string, error) {
+	if len(signature) != 65 {
+		return "", errors.New("a signature is 65 bytes")
+	}
+
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:64])
+	v := signature[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return "", errors.New("invalid recovery id in signature")
+	}
+	n := secp256k1.Order()
+	if r.Sign() == 0 || r.Cmp(n) >= 0 || s.Sign() == 0 || s.Cmp(n) >= 0 {
+		return "", errors.New("invalid signature values")
+	}
+	// (r, n - s) is as valid as (r, s), Ethereum only accepts the lower one
+	// so a signature can't be changed into another valid one (EIP-2)
+	if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		return "", errors.New("invalid signature, s is in the upper half of the curve order")
+	}
+
+	// R, the point whose x is r, with the parity of y from v
+	R, ok := secp256k1.PointFromX(r, v == 1)
+	if !ok {
+		return "", errors.New("invalid signature, r is not on the curve")
+	}
+
+	// Q = r⁻¹(sR - eG)
+	e := new(big.Int).SetBytes(HashMessage(message))
+	rInv := new(big.Int).ModInverse(r, n)
+	u1 := new(big.Int).Mul(e, rInv)
+	u1.Neg(u1).Mod(u1, n)
+	u2 := new(big.Int).Mul(s, rInv)
+	u2.Mod(u2, n)
+
+	pub := secp256k1.Generator().Mul(u1).Add(R.Mul(u2))
+	if pub.Infinity() {
+		return "", errors.New("invalid signature")
+	}
+
+	return pointAddress(pub), nil
+}
+
+// KeyAddress - the address of a private key
+func KeyAddress(key *big.Int) string {
+	return pointAddress(secp256k1.Generator().Mul(key))
+}
@@ -0,0 +1,105 @@
+package keystore
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/gladiusio/gladius-cli/internal/secp256k1"
+)
+
+// the example of web3.eth.accounts.sign
+const (
+	web3Key       = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	web3Address   = "0x2c7536e3605d9c16a7a3d7b1898e529396a65c23"
+	web3Message   = "Some data"
+	web3Hash      = "1da44b586eb0729ff70a73c326926f6ed5a25f5b056e7f47fbc6e58d86871655"
+	web3Signature = "0xb91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a0291c"
+)
+
+func TestHashMessage(t *testing.T) {
+	tests := map[string]string{
+		web3Message:   web3Hash,
+		"Hello World": "a1de988600a42c4b4ab089b619297c17d53cffae5d5120d82d8a92d0bb3b78f2",
+		"":            "5f35dce98ba4fba25530a026ed80b2cecdaa31091ba4958b99b52ea1d068adad",
+	}
+	for message, want := range tests {
+		if got := hex.EncodeToString(HashMessage([]byte(message))); got != want {
+			t.Errorf("HashMessage(%q) = %s, want %s", message, got, want)
+		}
+	}
+}
+
+func TestKeyAddress(t *testing.T) {
+	key, _ := new(big.Int).SetString(web3Key, 16)
+	if got := KeyAddress(key); got != web3Address {
+		t.Errorf("KeyAddress = %s, want %s", got, web3Address)
+	}
+	if got := KeyAddress(big.NewInt(1)); got != "0x7e5f4552091a69125d5dfcb7b8c2659029395bdf" {
+		t.Errorf("KeyAddress(1) = %s", got)
+	}
+}
+
+// withV - the signature with another recovery id
+func withV(sig []byte, v byte) []byte {
+	return append(append([]byte{}, sig[:64]...), v)
+}
+
+func TestRecoverAddress(t *testing.T) {
+	sig, err := ParseSignature(web3Signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := []byte(web3Message)
+
+	// the web3 signature has v = 28, some wallets write 1 for it
+	for _, v := range []byte{28, 1} {
+		got, err := RecoverAddress(message, withV(sig, v))
+		if err != nil || got != web3Address {
+			t.Errorf("v = %d: RecoverAddress = %s, %v, want %s", v, got, err, web3Address)
+		}
+	}
+
+	// the other parity is another key
+	for _, v := range []byte{27, 0} {
+		got, err := RecoverAddress(message, withV(sig, v))
+		if err == nil && got == web3Address {
+			t.Errorf("v = %d recovered the signer", v)
+		}
+	}
+
+	for _, v := range []byte{2, 26, 29, 37} {
+		if _, err := RecoverAddress(message, withV(sig, v)); err == nil {
+			t.Errorf("v = %d accepted", v)
+		}
+	}
+
+	if got, err := RecoverAddress([]byte("Some other data"), sig); err == nil && got == web3Address {
+		t.Error("the signature recovered the signer of another message")
+	}
+}
+
+// (r, n - s) with the other v recovers the same key, Ethereum refuses it
+func TestRecoverAddressHighS(t *testing.T) {
+	sig, _ := ParseSignature(web3Signature)
+	s := new(big.Int).SetBytes(sig[32:64])
+	highS := new(big.Int).Sub(secp256k1.Order(), s)
+
+	malleated := append(append(append([]byte{}, sig[:32]...), pad32(highS)...), 27)
+	_, err := RecoverAddress([]byte(web3Message), malleated)
+	if err == nil || !strings.Contains(err.Error(), "upper half") {
+		t.Errorf("high s: err = %v, want it refused", err)
+	}
+}
+
+func TestParseSignature(t *testing.T) {
+	for _, bad := range []string{"", "0x", "0x1234", web3Signature + "00", strings.Replace(web3Signature, "b9", "zz", 1)} {
+		if _, err := ParseSignature(bad); err == nil {
+			t.Errorf("ParseSignature(%q) accepted", bad)
+		}
+	}
+	if _, err := ParseSignature(strings.ToUpper(web3Signature[2:])); err != nil {
+		t.Errorf("upper case without 0x: %v", err)
+	}
+}
@@ -1,17 +1,19 @@
 package keystore
 
 import (
+	"context"
+	"encoding/hex"
 	"fmt"
 
+	"github.com/gladiusio/gladius-cli/config"
 	"github.com/gladiusio/gladius-cli/utils"
 	"github.com/mgutz/ansi"
 	log "github.com/sirupsen/logrus"
-	"gopkg.in/AlecAivazis/survey.v1/terminal"
 )
 
 // CreateAccount - create a new account with passphrase
-func CreateAccount() (string, error) {
-	url := "http://localhost:3001/api/keystore/account/create"
+func CreateAccount(ctx context.Context) (string, error) {
+	url := fmt.Sprintf("http://localhost:%d/api/keystore/account/create", config.GetInt("Ports.NetworkGateway"))
 
 	// make a new passphrase for this account
 	password := utils.NewPassphrase()
@@ -20,7 +22,7 @@ func CreateAccount() (string, error) {
 
 	utils.CachePassphrase(password)
 	log.WithFields(log.Fields{"file": "wallet.go", "func": "CreateAccount"}).Debug("POST: ", url)
-	res, err := utils.SendRequest("POST", url, pass)
+	res, err := utils.SendRequest(ctx, "POST", url, pass)
 	if err != nil {
 		return "", utils.HandleError(err, "", "wallet.CreateAccount")
 	}
@@ -35,18 +37,18 @@ func CreateAccount() (string, error) {
 	response := api.Response.(map[string]interface{})
 	address := response["address"].(string)
 
-	fmt.Println()
-	terminal.Println(ansi.Color("Account Address:", "83+hb"), ansi.Color(address, "255+hb"))
+	fmt.Fprintln(utils.Stdout)
+	fmt.Fprintln(utils.Stdout, ansi.Color("Account Address:", "83+hb"), ansi.Color(address, "255+hb"))
 
 	return "Account created", nil
 }
 
 // GetAccounts - get accounts at the standard config path
-func GetAccounts() (string, error) {
-	url := "http://localhost:3001/api/keystore/account"
+func GetAccounts(ctx context.Context) (string, error) {
+	url := fmt.Sprintf("http://localhost:%d/api/keystore/account", config.GetInt("Ports.NetworkGateway"))
 
 	log.WithFields(log.Fields{"file": "wallet.go", "func": "GetAccounts"}).Debug("POST: ", url)
-	res, err := utils.SendRequest("GET", url, nil)
+	res, err := utils.SendRequest(ctx, "GET", url, nil)
 	if err != nil {
 		return "", utils.HandleError(err, "", "wallet.GetAccounts")
 	}
@@ -67,11 +69,39 @@ func GetAccounts() (string, error) {
 }
 
 // EnsureAccount - make sure they have an account
-func EnsureAccount() (bool, error) {
-	_, err := GetAccounts()
+func EnsureAccount(ctx context.Context) (bool, error) {
+	_, err := GetAccounts(ctx)
 	if err != nil {
 		return false, err
 	}
 
 	return true, nil
 }
+
+// SignMessage - personal-sign message with the node's account, returns the
+// signature as hex. A locked wallet is unlocked by asking for the passphrase.
+func SignMessage(ctx context.Context, message []byte) (string, error) {
+	url := fmt.Sprintf("http://localhost:%d/api/keystore/account/sign", config.GetInt("Ports.NetworkGateway"))
+
+	// hex, so files that aren't text are sent as they are
+	body := map[string]string{"message": "0x" + hex.EncodeToString(message)}
+
+	log.WithFields(log.Fields{"file": "wallet.go", "func": "SignMessage"}).Debug("POST: ", url)
+	res, err := utils.SendRequest(ctx, "POST", url, body)
+	if err != nil {
+		return "", utils.HandleError(err, "", "wallet.SignMessage")
+	}
+
+	api, err := utils.ControlDaemonHandler([]byte(res))
+	if err != nil {
+		return "", utils.HandleError(err, "", "wallet.SignMessage")
+	}
+
+	response, _ := api.Response.(map[string]interface{})
+	signature, _ := response["signature"].(string)
+	if _, err = ParseSignature(signature); err != nil {
+		return "", utils.HandleError(err, "The Network Gateway returned an invalid signature", "wallet.SignMessage")
+	}
+
+	return signature, nil
+}
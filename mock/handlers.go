@@ -0,0 +1,481 @@
+package mock
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gladiusio/gladius-cli/keystore"
+)
+
+// apiResponse - same shape as utils.APIResponse
+type apiResponse struct {
+	Message  string      `json:"message"`
+	Success  bool        `json:"success"`
+	Error    string      `json:"error"`
+	Response interface{} `json:"response"`
+	TxHash   interface{} `json:"txHash"`
+	Endpoint string      `json:"endpoint"`
+}
+
+// transferBody - the body of the keystore transaction endpoints
+type transferBody struct {
+	To     string `json:"to"`
+	Symbol string `json:"symbol"`
+	Value  string `json:"value"` // in the smallest unit of the token
+}
+
+// gasPrice - 20 gwei, the price of gas on the fake chain
+var gasPrice = big.NewInt(20000000000)
+
+// transferGas - gas used to send a token, ETH transfers are cheaper than
+// token contract calls
+func transferGas(symbol string) int64 {
+	if strings.EqualFold(symbol, "eth") {
+		return 21000
+	}
+	return 60000
+}
+
+func respond(w http.ResponseWriter, r *http.Request, status int, message string, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(apiResponse{
+		Message:  message,
+		Success:  true,
+		Response: response,
+		Endpoint: r.URL.Path,
+	})
+}
+
+// respondTx - respond for a request that submitted a transaction
+func respondTx(w http.ResponseWriter, r *http.Request, message, tx string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(apiResponse{
+		Message:  message,
+		Success:  true,
+		TxHash:   tx,
+		Endpoint: r.URL.Path,
+	})
+}
+
+func fail(w http.ResponseWriter, r *http.Request, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(apiResponse{
+		Message:  message,
+		Success:  false,
+		Error:    message,
+		Endpoint: r.URL.Path,
+	})
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err != nil {
+		fail(w, r, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+	return true
+}
+
+// versionHandler - /version, served by every module
+func (d *Daemon) versionHandler(module string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		d.State.mu.Lock()
+		version := d.State.Versions[module]
+		d.State.mu.Unlock()
+
+		respond(w, r, http.StatusOK, "", map[string]string{"version": version})
+	}
+}
+
+func (d *Daemon) guardianHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/version", d.versionHandler("guardian"))
+
+	mux.HandleFunc("/service/set_timeout", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Timeout int `json:"timeout"`
+		}
+		if !decode(w, r, &body) {
+			return
+		}
+		d.State.Update(func(s *State) { s.Timeout = body.Timeout })
+		respond(w, r, http.StatusOK, "Timeout set", nil)
+	})
+
+	mux.HandleFunc("/service/set_state/all", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Running bool `json:"running"`
+		}
+		if !decode(w, r, &body) {
+			return
+		}
+		d.State.Update(func(s *State) { s.Running = body.Running })
+		respond(w, r, http.StatusOK, "State set", nil)
+	})
+
+	return mux
+}
+
+func (d *Daemon) edgedHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/version", d.versionHandler("edged"))
+	return mux
+}
+
+func (d *Daemon) networkGatewayHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/version", d.versionHandler("network-gateway"))
+
+	mux.HandleFunc("/api/keystore/account", func(w http.ResponseWriter, r *http.Request) {
+		d.State.mu.Lock()
+		account := d.State.Account
+		d.State.mu.Unlock()
+
+		if account == "" {
+			fail(w, r, http.StatusOK, "No account found")
+			return
+		}
+		respond(w, r, http.StatusOK, "", map[string]string{"address": account})
+	})
+
+	mux.HandleFunc("/api/keystore/account/create", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Passphrase string `json:"passphrase"`
+		}
+		if !decode(w, r, &body) {
+			return
+		}
+
+		d.State.mu.Lock()
+		defer d.State.mu.Unlock()
+		if d.State.Account == "" {
+			d.State.Account = accountAddress
+		}
+		d.State.Passphrase = body.Passphrase
+		d.State.Locked = false
+		respond(w, r, http.StatusOK, "Account created", map[string]string{"address": d.State.Account})
+	})
+
+	mux.HandleFunc("/api/keystore/account/open", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Passphrase string `json:"passphrase"`
+		}
+		if !decode(w, r, &body) {
+			return
+		}
+
+		d.State.mu.Lock()
+		defer d.State.mu.Unlock()
+		if d.State.Account == "" {
+			fail(w, r, http.StatusOK, "No account found")
+			return
+		}
+		if body.Passphrase != d.State.Passphrase {
+			fail(w, r, http.StatusOK, "Incorrect passphrase")
+			return
+		}
+		d.State.Locked = false
+		respond(w, r, http.StatusOK, "Account unlocked", map[string]bool{"unlocked": true})
+	})
+
+	mux.HandleFunc("/api/keystore/account/sign", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Message string `json:"message"` // hex
+		}
+		if !decode(w, r, &body) {
+			return
+		}
+		message, err := hex.DecodeString(strings.TrimPrefix(body.Message, "0x"))
+		if err != nil {
+			fail(w, r, http.StatusBadRequest, "Invalid message")
+			return
+		}
+
+		d.State.mu.Lock()
+		defer d.State.mu.Unlock()
+		if d.State.Account == "" {
+			fail(w, r, http.StatusOK, "No account found")
+			return
+		}
+		if d.State.Locked {
+			fail(w, r, http.StatusForbidden, "Wallet is locked")
+			return
+		}
+
+		// only the default account has a key, others sign as it would
+		signature := signHash(accountKey, keystore.HashMessage(message))
+		respond(w, r, http.StatusOK, "", map[string]string{"signature": "0x" + hex.EncodeToString(signature)})
+	})
+
+	// /api/account/<address>/balance/<symbol>
+	mux.HandleFunc("/api/account/", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/account/"), "/")
+		if len(parts) != 3 || parts[1] != "balance" {
+			fail(w, r, http.StatusNotFound, "Not found")
+			return
+		}
+
+		d.State.mu.Lock()
+		balance, ok := d.State.Balances[strings.ToLower(parts[2])]
+		d.State.mu.Unlock()
+		if !ok {
+			balance = "0"
+		}
+
+		respond(w, r, http.StatusOK, "", map[string]interface{}{"value": json.Number(balance)})
+	})
+
+	// /api/status/tx/<hash>
+	mux.HandleFunc("/api/status/tx/", func(w http.ResponseWriter, r *http.Request) {
+		hash := strings.ToLower(strings.TrimPrefix(r.URL.Path, "/api/status/tx/"))
+
+		d.State.mu.Lock()
+		defer d.State.mu.Unlock()
+
+		tx, ok := d.State.Transactions[hash]
+		if !ok {
+			fail(w, r, http.StatusOK, "Transaction not found")
+			return
+		}
+
+		status := map[string]interface{}{
+			"complete":     tx.Block > 0,
+			"success":      !tx.Failed,
+			"currentBlock": d.State.Block,
+		}
+		if tx.Block > 0 {
+			status["blockNumber"] = tx.Block
+			status["confirmations"] = d.State.Block - tx.Block + 1
+		}
+		respond(w, r, http.StatusOK, "", status)
+	})
+
+	mux.HandleFunc("/api/keystore/transaction/estimate", func(w http.ResponseWriter, r *http.Request) {
+		var body transferBody
+		if !decode(w, r, &body) {
+			return
+		}
+		respond(w, r, http.StatusOK, "", map[string]interface{}{"gas": transferGas(body.Symbol), "gasPrice": json.Number(gasPrice.String())})
+	})
+
+	mux.HandleFunc("/api/keystore/transaction/send", func(w http.ResponseWriter, r *http.Request) {
+		var body transferBody
+		if !decode(w, r, &body) {
+			return
+		}
+		value, ok := new(big.Int).SetString(body.Value, 10)
+		if !ok || value.Sign() <= 0 {
+			fail(w, r, http.StatusBadRequest, "Invalid value")
+			return
+		}
+		symbol := strings.ToLower(body.Symbol)
+		fee := new(big.Int).Mul(big.NewInt(transferGas(symbol)), gasPrice)
+
+		d.State.mu.Lock()
+		defer d.State.mu.Unlock()
+
+		if d.State.Locked {
+			fail(w, r, http.StatusForbidden, "Wallet is locked")
+			return
+		}
+
+		balance, _ := new(big.Int).SetString(d.State.Balances[symbol], 10)
+		eth, _ := new(big.Int).SetString(d.State.Balances["eth"], 10)
+		if balance == nil || eth == nil {
+			fail(w, r, http.StatusOK, "Insufficient funds")
+			return
+		}
+		balance.Sub(balance, value)
+		if symbol == "eth" {
+			balance.Sub(balance, fee)
+			eth = balance
+		} else {
+			eth.Sub(eth, fee)
+		}
+		if balance.Sign() < 0 || eth.Sign() < 0 {
+			fail(w, r, http.StatusOK, "Insufficient funds")
+			return
+		}
+
+		d.State.Balances[symbol] = balance.String()
+		d.State.Balances["eth"] = eth.String()
+		respondTx(w, r, "Transaction sent", d.State.addTransaction(body.To+"/"+body.Value))
+	})
+
+	mux.HandleFunc("/api/keystore/pgp/create", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r, http.StatusOK, "PGP key created", nil)
+	})
+
+	// /api/node/applications/<pool>/new, /api/node/applications/<pool>/view
+	// and /api/node/applications/<pool>/form
+	mux.HandleFunc("/api/node/applications/", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/node/applications/"), "/")
+		if len(parts) != 2 {
+			fail(w, r, http.StatusNotFound, "Not found")
+			return
+		}
+		pool, action := strings.ToLower(parts[0]), parts[1]
+
+		d.State.mu.Lock()
+		defer d.State.mu.Unlock()
+
+		switch action {
+		case "new":
+			if d.State.Locked {
+				fail(w, r, http.StatusForbidden, "Wallet is locked")
+				return
+			}
+			var profile map[string]interface{}
+			if !decode(w, r, &profile) {
+				return
+			}
+			d.State.Applications[pool] = &Application{Profile: profile, Pending: true}
+			respondTx(w, r, "Application sent", d.State.addTransaction(pool))
+		case "form":
+			fields, ok := d.State.Forms[pool]
+			if !ok {
+				fields = json.RawMessage("[]")
+			}
+			respond(w, r, http.StatusOK, "", map[string]interface{}{"fields": fields})
+		case "view":
+			app, ok := d.State.Applications[pool]
+			if !ok {
+				fail(w, r, http.StatusOK, "No application found")
+				return
+			}
+			profile := make(map[string]interface{})
+			for key, value := range app.Profile {
+				profile[key] = value
+			}
+			profile["pending"] = app.Pending
+			profile["approved"] = app.Approved
+			respond(w, r, http.StatusOK, "", map[string]interface{}{"profile": profile})
+		default:
+			fail(w, r, http.StatusNotFound, "Not found")
+		}
+	})
+
+	// /api/pool/applications/<pool>/list and
+	// /api/pool/applications/<pool>/<node>/approve|reject
+	mux.HandleFunc("/api/pool/applications/", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/pool/applications/"), "/")
+		pool := strings.ToLower(parts[0])
+
+		d.State.mu.Lock()
+		defer d.State.mu.Unlock()
+		received := d.State.Received[pool]
+
+		switch {
+		case len(parts) == 2 && parts[1] == "list":
+			nodes := make([]string, 0, len(received))
+			for node := range received {
+				nodes = append(nodes, node)
+			}
+			sort.Strings(nodes)
+
+			applications := make([]map[string]interface{}, 0, len(nodes))
+			for _, node := range nodes {
+				app := received[node]
+				applications = append(applications, map[string]interface{}{
+					"node":     node,
+					"profile":  app.Profile,
+					"pending":  app.Pending,
+					"approved": app.Approved,
+					"message":  app.Message,
+				})
+			}
+			respond(w, r, http.StatusOK, "", map[string]interface{}{"applications": applications})
+		case len(parts) == 3 && (parts[2] == "approve" || parts[2] == "reject"):
+			if d.State.Locked {
+				fail(w, r, http.StatusForbidden, "Wallet is locked")
+				return
+			}
+			app, ok := received[strings.ToLower(parts[1])]
+			if !ok {
+				fail(w, r, http.StatusOK, "No application from this node")
+				return
+			}
+			var body struct {
+				Message string `json:"message"`
+			}
+			if !decode(w, r, &body) {
+				return
+			}
+			app.Pending = false
+			app.Approved = parts[2] == "approve"
+			app.Message = body.Message
+			message := "Application rejected"
+			if app.Approved {
+				message = "Application approved"
+			}
+			respondTx(w, r, message, d.State.addTransaction(pool+"/"+parts[1]))
+		default:
+			fail(w, r, http.StatusNotFound, "Not found")
+		}
+	})
+
+	return mux
+}
+
+// controlHandler - GET returns the state, PUT replaces it, so demos and
+// scripts can change the fake modules while they run
+func (d *Daemon) controlHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/state", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			d.State.mu.Lock()
+			defer d.State.mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(d.State)
+		case "PUT":
+			next := NewState()
+			if !decode(w, r, next) {
+				return
+			}
+			if next.Applications == nil {
+				next.Applications = make(map[string]*Application)
+			}
+			if next.Forms == nil {
+				next.Forms = make(map[string]json.RawMessage)
+			}
+			if next.Received == nil {
+				next.Received = make(map[string]map[string]*Application)
+			}
+			if next.Balances == nil {
+				next.Balances = make(map[string]string)
+			}
+			if next.Transactions == nil {
+				next.Transactions = make(map[string]*Transaction)
+			}
+			d.State.Update(func(s *State) {
+				s.Versions = next.Versions
+				s.Running = next.Running
+				s.Timeout = next.Timeout
+				s.Account = next.Account
+				s.Passphrase = next.Passphrase
+				s.Locked = next.Locked
+				s.Applications = next.Applications
+				s.Forms = next.Forms
+				s.Received = next.Received
+				s.Balances = next.Balances
+				s.Block = next.Block
+				s.Transactions = next.Transactions
+			})
+			for _, module := range Modules {
+				d.SetOffline(module, next.Offline[module])
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	return mux
+}
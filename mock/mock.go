@@ -0,0 +1,180 @@
+package mock
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Modules - names of the modules the daemon fakes, as in config.Modules
+var Modules = []string{"guardian", "edged", "network-gateway"}
+
+// Daemon - fake Guardian, EdgeD and Network Gateway listening on localhost
+type Daemon struct {
+	State     *State
+	BlockTime time.Duration // mine a block this often once started, 0 only mines on State.Mine
+
+	mu       sync.Mutex
+	handlers map[string]http.Handler
+	ports    map[string]int
+	servers  map[string]*http.Server
+	stop     chan struct{}
+}
+
+// New - fake modules backed by state, or NewState() if state is nil
+func New(state *State) *Daemon {
+	if state == nil {
+		state = NewState()
+	}
+
+	d := &Daemon{
+		State:   state,
+		ports:   make(map[string]int),
+		servers: make(map[string]*http.Server),
+		stop:    make(chan struct{}),
+	}
+	d.handlers = map[string]http.Handler{
+		"guardian":        d.guardianHandler(),
+		"edged":           d.edgedHandler(),
+		"network-gateway": d.networkGatewayHandler(),
+		"control":         d.controlHandler(),
+	}
+
+	return d
+}
+
+// Start - listen for every module on its port, 0 picks a free port. A
+// "control" port serves the control API. Modules marked offline in the
+// state are not started.
+func (d *Daemon) Start(ports map[string]int) error {
+	d.mu.Lock()
+	for name, port := range ports {
+		if _, ok := d.handlers[name]; !ok {
+			d.mu.Unlock()
+			return fmt.Errorf("unknown module %s", name)
+		}
+		d.ports[name] = port
+	}
+	d.mu.Unlock()
+
+	for name := range ports {
+		d.State.mu.Lock()
+		offline := d.State.Offline[name]
+		d.State.mu.Unlock()
+		if offline {
+			continue
+		}
+
+		err := d.listen(name)
+		if err != nil {
+			d.Close()
+			return err
+		}
+	}
+
+	if d.BlockTime > 0 {
+		go d.mine()
+	}
+
+	return nil
+}
+
+// mine - a block every BlockTime until the daemon is closed
+func (d *Daemon) mine() {
+	ticker := time.NewTicker(d.BlockTime)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			d.State.Mine()
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+func (d *Daemon) listen(name string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.servers[name]; ok {
+		return nil
+	}
+
+	l, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", d.ports[name]))
+	if err != nil {
+		return fmt.Errorf("%s: %s", name, err)
+	}
+
+	// keep the port so the module comes back on it after being offline
+	d.ports[name] = l.Addr().(*net.TCPAddr).Port
+
+	server := &http.Server{Handler: d.handlers[name]}
+	d.servers[name] = server
+	go server.Serve(l)
+
+	return nil
+}
+
+// Ports - the port every module listens on
+func (d *Daemon) Ports() map[string]int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ports := make(map[string]int)
+	for name, port := range d.ports {
+		ports[name] = port
+	}
+	return ports
+}
+
+// SetOffline - stop a module so connections to it are refused, or start it
+// again on the same port
+func (d *Daemon) SetOffline(module string, offline bool) error {
+	d.State.Update(func(s *State) {
+		if s.Offline == nil {
+			s.Offline = make(map[string]bool)
+		}
+		s.Offline[module] = offline
+	})
+
+	if !offline {
+		d.mu.Lock()
+		_, started := d.ports[module]
+		d.mu.Unlock()
+		if !started {
+			return nil
+		}
+		return d.listen(module)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	server, ok := d.servers[module]
+	if !ok {
+		return nil
+	}
+	delete(d.servers, module)
+	return server.Close()
+}
+
+// Close - stop every module
+func (d *Daemon) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for name, server := range d.servers {
+		server.Close()
+		delete(d.servers, name)
+	}
+
+	select {
+	case <-d.stop:
+	default:
+		close(d.stop)
+	}
+	return nil
+}
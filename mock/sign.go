@@ -0,0 +1,96 @@
+package mock
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/gladiusio/gladius-cli/internal/secp256k1"
+)
+
+// signHash - a signature of hash by key, with v of 27 or 28 like
+// personal-sign, as the Network Gateway would make it
+func signHash(key *big.Int, hash []byte) []byte {
+	n := secp256k1.Order()
+	e := new(big.Int).SetBytes(hash)
+
+	nonces := newNonces(key, hash, n)
+	for {
+		k := nonces.next()
+		R := secp256k1.Generator().Mul(k)
+		r := new(big.Int).Mod(R.X, n)
+		if r.Sign() == 0 {
+			continue
+		}
+		s := new(big.Int).Mul(r, key)
+		s.Add(s, e).Mul(s, new(big.Int).ModInverse(k, n)).Mod(s, n)
+		if s.Sign() == 0 {
+			continue
+		}
+
+		v := byte(R.Y.Bit(0))
+		// low s like Ethereum, which flips the parity of R
+		if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+			s.Sub(n, s)
+			v ^= 1
+		}
+
+		return append(append(pad32(r), pad32(s)...), 27+v)
+	}
+}
+
+// nonces - the deterministic nonces of RFC 6979 with HMAC-SHA256, the ones
+// Ethereum wallets use, so the signatures are the same as theirs
+type nonces struct {
+	n    *big.Int
+	k, v []byte
+	used bool
+}
+
+// newNonces - RFC 6979 3.2 steps a to f, for a 256 bit hash and curve order
+func newNonces(key *big.Int, hash []byte, n *big.Int) *nonces {
+	h := new(big.Int).SetBytes(hash)
+	h.Mod(h, n)
+	seed := append(pad32(key), pad32(h)...)
+
+	g := &nonces{n: n, k: make([]byte, 32), v: make([]byte, 32)}
+	for i := range g.v {
+		g.v[i] = 0x01
+	}
+	g.k = g.mac(g.v, []byte{0x00}, seed)
+	g.v = g.mac(g.v)
+	g.k = g.mac(g.v, []byte{0x01}, seed)
+	g.v = g.mac(g.v)
+	return g
+}
+
+// next - RFC 6979 3.2 step h, the following candidate if one was rejected
+func (g *nonces) next() *big.Int {
+	for {
+		if g.used {
+			g.k = g.mac(g.v, []byte{0x00})
+			g.v = g.mac(g.v)
+		}
+		g.used = true
+
+		g.v = g.mac(g.v)
+		k := new(big.Int).SetBytes(g.v)
+		if k.Sign() > 0 && k.Cmp(g.n) < 0 {
+			return k
+		}
+	}
+}
+
+func (g *nonces) mac(data ...[]byte) []byte {
+	m := hmac.New(sha256.New, g.k)
+	for _, b := range data {
+		m.Write(b)
+	}
+	return m.Sum(nil)
+}
+
+// pad32 - n as a 32 byte big endian number
+func pad32(n *big.Int) []byte {
+	b := n.Bytes()
+	return append(make([]byte, 32-len(b)), b...)
+}
@@ -0,0 +1,45 @@
+package mock
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/gladiusio/gladius-cli/internal/secp256k1"
+	"github.com/gladiusio/gladius-cli/keystore"
+)
+
+// the signature web3.eth.accounts.sign makes, wallets use the same
+// deterministic nonces
+func TestSignHashWeb3(t *testing.T) {
+	key, _ := new(big.Int).SetString("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318", 16)
+	want := "b91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a0291c"
+
+	sig := signHash(key, keystore.HashMessage([]byte("Some data")))
+	if got := hex.EncodeToString(sig); got != want {
+		t.Errorf("signature = %s, want %s", got, want)
+	}
+}
+
+func TestSignHashRecovers(t *testing.T) {
+	half := new(big.Int).Rsh(secp256k1.Order(), 1)
+	for i := 0; i < 20; i++ {
+		message := []byte{byte(i), 'm', 's', 'g'}
+		sig := signHash(accountKey, keystore.HashMessage(message))
+
+		if s := new(big.Int).SetBytes(sig[32:64]); s.Cmp(half) > 0 {
+			t.Errorf("message %d: high s", i)
+		}
+		if sig[64] != 27 && sig[64] != 28 {
+			t.Errorf("message %d: v = %d", i, sig[64])
+		}
+
+		signer, err := keystore.RecoverAddress(message, sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if signer != keystore.KeyAddress(accountKey) {
+			t.Errorf("message %d: recovered %s", i, signer)
+		}
+	}
+}
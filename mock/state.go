@@ -0,0 +1,191 @@
+// Package mock is a fake Guardian, EdgeD and Network Gateway implementing the
+// endpoints the CLI calls, so commands can be tried and tested without a
+// real node. Its state can be changed from Go, with flags of
+// `gladius dev mock`, or over its control API.
+package mock
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/gladiusio/gladius-cli/keystore"
+	"github.com/gladiusio/gladius-cli/utils"
+)
+
+// accountKey - private key of the fake wallet, sha256 of "gladius mock
+// wallet", so the messages it signs can be verified
+var accountKey, _ = new(big.Int).SetString("40f1764addcbf0be4aac8e7b6b69beeb4990a79b00e271cee3ab25d5712ed4f6", 16)
+
+// accountAddress - address of accountKey
+var accountAddress = utils.ChecksumAddress(keystore.KeyAddress(accountKey))
+
+// Application - a node's application to a pool
+type Application struct {
+	Profile  map[string]interface{} `json:"profile"`
+	Pending  bool                   `json:"pending"`
+	Approved bool                   `json:"approved"`
+	Message  string                 `json:"message,omitempty"` // sent by the pool with its decision
+}
+
+// Transaction - a transaction sent through the Network Gateway
+type Transaction struct {
+	Block  int64 `json:"block"`  // block it was mined in, 0 while pending
+	Failed bool  `json:"failed"` // mined but reverted
+}
+
+// State - everything the fake modules know. Use the methods to change it
+// while the daemon is running.
+type State struct {
+	mu sync.Mutex
+
+	Versions     map[string]string                  `json:"versions"`     // module name -> version
+	Offline      map[string]bool                    `json:"offline"`      // module name -> refusing connections
+	Running      bool                               `json:"running"`      // EdgeD and Network Gateway started by the Guardian
+	Timeout      int                                `json:"timeout"`      // last timeout set on the Guardian
+	Account      string                             `json:"account"`      // wallet address, "" when there is no wallet yet
+	Passphrase   string                             `json:"passphrase"`   // passphrase of the wallet
+	Locked       bool                               `json:"locked"`       // wallet needs to be unlocked before applying
+	Applications map[string]*Application            `json:"applications"` // pool address (lower case) -> application
+	Forms        map[string]json.RawMessage         `json:"forms"`        // pool address (lower case) -> fields of its application form
+	Received     map[string]map[string]*Application `json:"received"`     // pool address (lower case) -> node address (lower case) -> application to a pool the account runs
+	Balances     map[string]string                  `json:"balances"`     // token symbol (lower case) -> balance of every account in its smallest unit
+	Block        int64                              `json:"block"`        // latest block
+	Transactions map[string]*Transaction            `json:"transactions"` // tx hash (lower case) -> transaction
+}
+
+// NewState - a node with a locked wallet, every module online and no
+// applications
+func NewState() *State {
+	return &State{
+		Versions: map[string]string{
+			"guardian":        "0.8.0",
+			"edged":           "0.8.0",
+			"network-gateway": "0.8.0",
+		},
+		Offline:      make(map[string]bool),
+		Account:      accountAddress,
+		Passphrase:   "password",
+		Locked:       true,
+		Applications: make(map[string]*Application),
+		Forms:        make(map[string]json.RawMessage),
+		Received:     make(map[string]map[string]*Application),
+		Balances: map[string]string{
+			"eth": "1500000000000000000",
+			"gla": "250000000000",
+		},
+		Block:        1,
+		Transactions: make(map[string]*Transaction),
+	}
+}
+
+// Update - change the state while holding its lock
+func (s *State) Update(fn func(s *State)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s)
+}
+
+// SetLocked - lock or unlock the wallet
+func (s *State) SetLocked(locked bool) {
+	s.Update(func(s *State) { s.Locked = locked })
+}
+
+// SetAccount - set the wallet address, "" removes the wallet
+func (s *State) SetAccount(address, passphrase string) {
+	s.Update(func(s *State) {
+		s.Account = address
+		s.Passphrase = passphrase
+	})
+}
+
+// SetApplication - set the status of the application to a pool, creating it
+// with an empty profile if needed. status is pending, approved or rejected.
+func (s *State) SetApplication(pool, status string) {
+	s.Update(func(s *State) {
+		app, ok := s.Applications[strings.ToLower(pool)]
+		if !ok {
+			app = &Application{Profile: make(map[string]interface{})}
+			s.Applications[strings.ToLower(pool)] = app
+		}
+		app.Pending = status == "pending"
+		app.Approved = status == "approved"
+	})
+}
+
+// Application - copy of the application to a pool
+func (s *State) Application(pool string) (Application, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	app, ok := s.Applications[strings.ToLower(pool)]
+	if !ok {
+		return Application{}, false
+	}
+	return *app, true
+}
+
+// mockCountries - where the nodes of AddReceived are, in turn
+var mockCountries = []struct{ name, code string }{
+	{"Germany", "DE"}, {"United States", "US"}, {"Japan", "JP"}, {"Brazil", "BR"}, {"France", "FR"},
+}
+
+// AddReceived - n pending applications to pool from made up nodes, in
+// different countries and with different bandwidths
+func (s *State) AddReceived(pool string, n int) {
+	s.Update(func(s *State) {
+		received, ok := s.Received[strings.ToLower(pool)]
+		if !ok {
+			received = make(map[string]*Application)
+			s.Received[strings.ToLower(pool)] = received
+		}
+		start := len(received)
+		for i := start; i < start+n; i++ {
+			sum := sha256.Sum256([]byte(fmt.Sprintf("gladius mock node %d", i)))
+			node := strings.ToLower(utils.ChecksumAddress(hex.EncodeToString(sum[:20])))
+			country := mockCountries[i%len(mockCountries)]
+			received[node] = &Application{
+				Profile: map[string]interface{}{
+					"name":           fmt.Sprintf("Node %d", i+1),
+					"email":          fmt.Sprintf("node%d@example.com", i+1),
+					"location":       country.name,
+					"locationCode":   country.code,
+					"estimatedSpeed": fmt.Sprint(50 * (i%4 + 1)),
+					"bio":            "Mock node",
+				},
+				Pending: true,
+			}
+		}
+	})
+}
+
+// AddTransaction - a pending transaction, mined by the next call to Mine
+func (s *State) AddTransaction(seed string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addTransaction(seed)
+}
+
+// addTransaction - AddTransaction with the lock held
+func (s *State) addTransaction(seed string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%d/%d", seed, s.Block, len(s.Transactions))))
+	hash := "0x" + hex.EncodeToString(sum[:])
+	s.Transactions[hash] = &Transaction{}
+	return hash
+}
+
+// Mine - add a block containing every pending transaction
+func (s *State) Mine() {
+	s.Update(func(s *State) {
+		s.Block++
+		for _, tx := range s.Transactions {
+			if tx.Block == 0 {
+				tx.Block = s.Block
+			}
+		}
+	})
+}
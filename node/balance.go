@@ -0,0 +1,65 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/gladiusio/gladius-cli/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// Token - a currency an account holds
+type Token struct {
+	Symbol   string
+	Decimals int // digits of the smallest unit, 18 for ETH (wei)
+}
+
+// Tokens - the balances shown by the CLI
+var Tokens = []Token{
+	{Symbol: "ETH", Decimals: 18},
+	{Symbol: "GLA", Decimals: 8},
+}
+
+// Balance - how much of a token an account holds
+type Balance struct {
+	Token  Token
+	Amount *big.Int // in the smallest unit of the token
+}
+
+// String - the amount in whole tokens
+func (b Balance) String() string {
+	return utils.FormatUnits(b.Amount, b.Token.Decimals)
+}
+
+// RequireGateway - fail clearly when the Network Gateway, which answers for
+// the wallet and the blockchain, is not running
+func RequireGateway(ctx context.Context) error {
+	status, _ := ProbeModule(ctx, "network-gateway")
+	if status.Online {
+		return nil
+	}
+
+	msg := "The Network Gateway is not running, start it with `gladius start`"
+	return utils.HandleError(errors.New(msg), msg, "node.RequireGateway")
+}
+
+// GetBalances - balance of every token in Tokens held by address
+func GetBalances(ctx context.Context, address string) ([]Balance, error) {
+	err := RequireGateway(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var balances []Balance
+	for _, token := range Tokens {
+		log.WithFields(log.Fields{"file": "balance.go", "func": "GetBalances", "token": token.Symbol}).Debug("Checking balance")
+		amount, err := utils.CheckBalance(ctx, address, token.Symbol)
+		if err != nil {
+			return nil, utils.HandleError(err, "", "node.GetBalances")
+		}
+		balances = append(balances, Balance{Token: token, Amount: amount})
+	}
+
+	return balances, nil
+}
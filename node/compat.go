@@ -0,0 +1,190 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gladiusio/gladius-cli/utils"
+)
+
+// Compatibility - the versions of a module this CLI supports
+type Compatibility struct {
+	Module     string // module name, see config.Modules
+	MinVersion string // oldest supported version
+	MaxVersion string // first version that is no longer supported, "" if open ended
+}
+
+// Endpoint - an endpoint the CLI calls and the first module version that
+// implements it
+type Endpoint struct {
+	Module   string
+	Path     string // the route, with <placeholders> for the parts the request fills in
+	Since    string
+	Expected bool // Since is the release it is planned for, not one it shipped in: older modules are warned about instead of refused
+}
+
+// CompatibilityMatrix - module versions this CLI was built against. 0.7.0 is
+// the release the CLI was written for; no release is known to break it, so
+// there is no maximum.
+var CompatibilityMatrix = []Compatibility{
+	{Module: "guardian", MinVersion: "0.7.0"},
+	{Module: "edged", MinVersion: "0.7.0"},
+	{Module: "network-gateway", MinVersion: "0.7.0"},
+}
+
+// Endpoints - every endpoint the CLI calls, commands list the paths they use
+// so an old module is caught before it is called. The 0.7.0 endpoints are the
+// ones the CLI already called against the 0.7.0 modules. The others are not in
+// the changelog of a released module yet, they are expected in 0.8.0.
+var Endpoints = []Endpoint{
+	{Module: "guardian", Path: "/service/set_timeout", Since: "0.7.0"},
+	{Module: "guardian", Path: "/service/set_state/all", Since: "0.7.0"},
+	{Module: "network-gateway", Path: "/api/account/<address>/balance/<symbol>", Since: "0.7.0"},
+	{Module: "network-gateway", Path: "/api/keystore/account", Since: "0.7.0"},
+	{Module: "network-gateway", Path: "/api/keystore/account/create", Since: "0.7.0"},
+	{Module: "network-gateway", Path: "/api/keystore/account/open", Since: "0.7.0"},
+	{Module: "network-gateway", Path: "/api/keystore/account/sign", Since: "0.8.0", Expected: true},
+	{Module: "network-gateway", Path: "/api/keystore/transaction/estimate", Since: "0.8.0", Expected: true},
+	{Module: "network-gateway", Path: "/api/keystore/transaction/send", Since: "0.8.0", Expected: true},
+	{Module: "network-gateway", Path: "/api/node/applications/<pool>/form", Since: "0.8.0", Expected: true},
+	{Module: "network-gateway", Path: "/api/node/applications/<pool>/new", Since: "0.7.0"},
+	{Module: "network-gateway", Path: "/api/node/applications/<pool>/view", Since: "0.7.0"},
+	{Module: "network-gateway", Path: "/api/pool/applications/<pool>/<node>/approve", Since: "0.8.0", Expected: true},
+	{Module: "network-gateway", Path: "/api/pool/applications/<pool>/list", Since: "0.8.0", Expected: true},
+	{Module: "network-gateway", Path: "/api/pool/applications/<pool>/<node>/reject", Since: "0.8.0", Expected: true},
+	{Module: "network-gateway", Path: "/api/status/tx/<hash>", Since: "0.7.0"},
+}
+
+// CompatibilityReport - how a running module compares to the versions this
+// CLI supports
+type CompatibilityReport struct {
+	Status    ModuleStatus
+	Supported Compatibility
+	OK        bool   // online and within the supported versions
+	Problem   string // why it is not OK
+}
+
+// CheckCompatibility - compare every module with the compatibility matrix
+func CheckCompatibility(ctx context.Context) []CompatibilityReport {
+	var reports []CompatibilityReport
+	for _, status := range ProbeModules(ctx) {
+		report := CompatibilityReport{Status: status, OK: true}
+		for _, c := range CompatibilityMatrix {
+			if c.Module == status.Module.Name {
+				report.Supported = c
+			}
+		}
+
+		if !status.Online {
+			report.OK = false
+			report.Problem = "not online"
+		} else if problem := versionProblem(status.Version, report.Supported); problem != "" {
+			report.OK = false
+			report.Problem = problem
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports
+}
+
+// versionProblem - why version is outside the supported range, "" if it isn't
+func versionProblem(version string, supported Compatibility) string {
+	if supported.MinVersion != "" {
+		cmp, err := utils.CompareVersions(version, supported.MinVersion)
+		if err != nil {
+			return err.Error()
+		}
+		if cmp < 0 {
+			return fmt.Sprintf("older than %s", supported.MinVersion)
+		}
+	}
+
+	if supported.MaxVersion != "" {
+		cmp, err := utils.CompareVersions(version, supported.MaxVersion)
+		if err != nil {
+			return err.Error()
+		}
+		if cmp >= 0 {
+			return fmt.Sprintf("%s or newer is not supported by this CLI", supported.MaxVersion)
+		}
+	}
+
+	return ""
+}
+
+// CheckEndpoints - make sure the running modules implement the given
+// endpoints. Returns an error for a module that is too old, and warnings for
+// modules outside the supported versions or older than the release an
+// endpoint is expected in. Offline modules, and modules
+// reporting a version that can't be parsed, are not checked.
+func CheckEndpoints(ctx context.Context, paths []string) ([]string, error) {
+	var warnings []string
+	warned := make(map[string]bool)
+
+	for _, path := range paths {
+		endpoint, ok := findEndpoint(path)
+		if !ok {
+			return warnings, fmt.Errorf("unknown endpoint %s", path)
+		}
+
+		status, _ := ProbeModule(ctx, endpoint.Module)
+		if !status.Online {
+			continue
+		}
+
+		if tooOld(status, endpoint) {
+			if endpoint.Expected {
+				warnings = append(warnings, fmt.Sprintf("%s %s may not implement %s (expected in %s), trying anyway", endpoint.Module, status.Version, endpoint.Path, endpoint.Since))
+			} else {
+				msg := fmt.Sprintf("%s %s does not implement %s (needs %s or newer), please update it", endpoint.Module, status.Version, endpoint.Path, endpoint.Since)
+				return warnings, utils.HandleError(errors.New(msg), msg, "node.CheckEndpoints")
+			}
+		}
+
+		if warned[endpoint.Module] {
+			continue
+		}
+		warned[endpoint.Module] = true
+
+		for _, c := range CompatibilityMatrix {
+			if c.Module != endpoint.Module {
+				continue
+			}
+			if problem := versionProblem(status.Version, c); problem != "" {
+				warnings = append(warnings, fmt.Sprintf("%s %s is not supported by this CLI (%s)", endpoint.Module, status.Version, problem))
+			}
+		}
+	}
+
+	return warnings, nil
+}
+
+// Implements - false when the running module is older than the release the
+// endpoint is in, or expected in. Offline modules and versions that can't be
+// parsed are assumed to implement it.
+func Implements(ctx context.Context, path string) bool {
+	endpoint, ok := findEndpoint(path)
+	if !ok {
+		return false
+	}
+	status, _ := ProbeModule(ctx, endpoint.Module)
+	return !status.Online || !tooOld(status, endpoint)
+}
+
+// tooOld - the module reports a version older than Since
+func tooOld(status ModuleStatus, endpoint Endpoint) bool {
+	cmp, err := utils.CompareVersions(status.Version, endpoint.Since)
+	return err == nil && cmp < 0
+}
+
+func findEndpoint(path string) (Endpoint, bool) {
+	for _, endpoint := range Endpoints {
+		if endpoint.Path == path {
+			return endpoint, true
+		}
+	}
+	return Endpoint{}, false
+}
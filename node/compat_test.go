@@ -0,0 +1,166 @@
+package node
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gladiusio/gladius-cli/config"
+	"github.com/gladiusio/gladius-cli/utils"
+)
+
+const (
+	openPath = "/api/keystore/account/open"         // in 0.7.0
+	signPath = "/api/keystore/account/sign"         // expected in 0.8.0
+	listPath = "/api/pool/applications/<pool>/list" // expected in 0.8.0
+)
+
+func TestCheckEndpoints(t *testing.T) {
+	tests := []struct {
+		version  string // of the network gateway, offline when empty
+		paths    []string
+		warnings []string // parts of each warning
+		err      string   // part of the error, none when empty
+	}{
+		{"0.7.0", []string{openPath}, nil, ""},
+		{"0.7.0", []string{openPath, signPath, listPath}, []string{"may not implement " + signPath + " (expected in 0.8.0)", "may not implement " + listPath}, ""},
+		{"v0.7.3", []string{signPath}, []string{"0.7.3 may not implement"}, ""},
+		{"0.8.0", []string{openPath, signPath, listPath}, nil, ""},
+		{"0.8", []string{signPath}, nil, ""},
+		{"0.8.0-rc1", []string{signPath}, nil, ""},
+		{"1.2.0", []string{openPath, signPath}, nil, ""},
+		{"0.6.9", []string{openPath}, nil, "0.6.9 does not implement " + openPath + " (needs 0.7.0 or newer)"},
+		{"0.6.9", []string{signPath, openPath}, []string{"may not implement", "not supported by this CLI (older than 0.7.0)"}, "does not implement " + openPath},
+		{"dev", []string{openPath, signPath}, []string{`not supported by this CLI (invalid version "dev")`}, ""},
+		{"", []string{openPath, signPath}, nil, ""},
+		{"0.8.0", []string{"/api/unknown"}, nil, "unknown endpoint /api/unknown"},
+	}
+
+	for _, test := range tests {
+		versions := map[string]string{}
+		if test.version != "" {
+			versions["network-gateway"] = test.version
+		}
+		ctx := useFakeModules(t, &fakeModules{versions: versions})
+
+		warnings, err := CheckEndpoints(ctx, test.paths)
+		name := test.version + " " + strings.Join(test.paths, ",")
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%s: %v", name, err)
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%s: error %v, want %q", name, err, test.err)
+		}
+		if len(warnings) != len(test.warnings) {
+			t.Errorf("%s: warnings %q, want %q", name, warnings, test.warnings)
+			continue
+		}
+		for i := range warnings {
+			if !strings.Contains(warnings[i], test.warnings[i]) {
+				t.Errorf("%s: warning %q, want %q", name, warnings[i], test.warnings[i])
+			}
+		}
+	}
+}
+
+// a module that is too old is refused with a message for the user
+func TestCheckEndpointsError(t *testing.T) {
+	ctx := useFakeModules(t, &fakeModules{versions: map[string]string{"network-gateway": "0.6.0"}})
+	_, err := CheckEndpoints(ctx, []string{openPath})
+	response, ok := err.(*utils.ErrorResponse)
+	if !ok || !strings.Contains(response.Message(), "please update it") {
+		t.Errorf("error %v, want an ErrorResponse asking to update", err)
+	}
+}
+
+func TestImplements(t *testing.T) {
+	tests := []struct {
+		version    string // of the network gateway, offline when empty
+		path       string
+		implements bool
+	}{
+		{"0.6.9", openPath, false},
+		{"0.7.0", openPath, true},
+		{"0.7.0", signPath, false},
+		{"0.7.99", listPath, false},
+		{"0.8.0", signPath, true},
+		{"v0.8.0+build5", signPath, true},
+		{"0.8.0-rc1", signPath, true},
+		{"0.9", listPath, true},
+		{"dev", signPath, true},
+		{"", signPath, true},
+		{"0.8.0", "/api/unknown", false},
+	}
+
+	for _, test := range tests {
+		versions := map[string]string{}
+		if test.version != "" {
+			versions["network-gateway"] = test.version
+		}
+		ctx := useFakeModules(t, &fakeModules{versions: versions})
+		if got := Implements(ctx, test.path); got != test.implements {
+			t.Errorf("%q implements %s: %v, want %v", test.version, test.path, got, test.implements)
+		}
+	}
+}
+
+func TestVersionProblem(t *testing.T) {
+	supported := Compatibility{Module: "edged", MinVersion: "0.7.0", MaxVersion: "0.9.0"}
+	tests := []struct {
+		version, problem string
+	}{
+		{"0.7.0", ""},
+		{"0.8.5", ""},
+		{"0.8.99", ""},
+		{"0.6.9", "older than 0.7.0"},
+		{"0.9.0", "0.9.0 or newer is not supported by this CLI"},
+		{"1.0.0", "0.9.0 or newer is not supported by this CLI"},
+		{"latest", `invalid version "latest"`},
+	}
+
+	for _, test := range tests {
+		if problem := versionProblem(test.version, supported); problem != test.problem {
+			t.Errorf("versionProblem(%s) = %q, want %q", test.version, problem, test.problem)
+		}
+	}
+
+	if problem := versionProblem("0.1.0", Compatibility{Module: "edged"}); problem != "" {
+		t.Errorf("no supported range: %q", problem)
+	}
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	ctx := useFakeModules(t, &fakeModules{versions: map[string]string{"guardian": "0.7.2", "network-gateway": "0.6.1"}})
+
+	problems := map[string]string{}
+	for _, report := range CheckCompatibility(ctx) {
+		if report.Supported.Module != report.Status.Module.Name {
+			t.Errorf("%s compared with the range of %q", report.Status.Module.Name, report.Supported.Module)
+		}
+		if report.OK != (report.Problem == "") {
+			t.Errorf("%s: OK %v with problem %q", report.Status.Module.Name, report.OK, report.Problem)
+		}
+		problems[report.Status.Module.Name] = report.Problem
+	}
+
+	want := map[string]string{"guardian": "", "edged": "not online", "network-gateway": "older than 0.7.0"}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("problems %v, want %v", problems, want)
+	}
+}
+
+// every endpoint belongs to a module the matrix covers, with a version it
+// can be compared with
+func TestEndpointsKnown(t *testing.T) {
+	for _, endpoint := range Endpoints {
+		if _, err := config.ModulePort(endpoint.Module); err != nil {
+			t.Errorf("%s: %v", endpoint.Path, err)
+		}
+		if _, err := utils.CompareVersions(endpoint.Since, "0.0.0"); err != nil {
+			t.Errorf("%s: %v", endpoint.Path, err)
+		}
+		if _, ok := findEndpoint(endpoint.Path); !ok {
+			t.Errorf("%s can't be found", endpoint.Path)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package node
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gladiusio/gladius-cli/config"
+	"github.com/gladiusio/gladius-cli/utils"
+)
+
+// Draft - the answers of an application that wasn't sent yet, so it can be
+// resumed. Sensitive answers are never saved in it.
+type Draft struct {
+	Answers map[string]interface{} `json:"answers"` // question name -> answer
+	Updated time.Time              `json:"updated"`
+}
+
+// DraftPath - where the application draft is kept
+func DraftPath() (string, error) {
+	base, err := config.GetGladiusBase()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "application-draft.json"), nil
+}
+
+// LoadDraft - the saved draft, nil when there is none
+func LoadDraft() (*Draft, error) {
+	path, err := DraftPath()
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var draft Draft
+	err = json.Unmarshal(b, &draft)
+	if err != nil {
+		return nil, err
+	}
+	if draft.Answers == nil {
+		draft.Answers = make(map[string]interface{})
+	}
+	return &draft, nil
+}
+
+// Save - write the draft, readable only by the user
+func (d *Draft) Save() error {
+	path, err := DraftPath()
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	d.Updated = utils.Now()
+	b, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// written next to it first, a Ctrl-C can't leave half a draft
+	tmp := path + ".tmp"
+	err = ioutil.WriteFile(tmp, b, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// RemoveDraft - forget the draft once the application is sent
+func RemoveDraft() error {
+	path, err := DraftPath()
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
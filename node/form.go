@@ -0,0 +1,139 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gladiusio/gladius-cli/config"
+	"github.com/gladiusio/gladius-cli/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// Types of the fields of an application form
+const (
+	FieldText        = "text"
+	FieldNumber      = "number"
+	FieldEmail       = "email"
+	FieldChoice      = "choice"      // one of Choices
+	FieldMultiChoice = "multichoice" // any of Choices
+	FieldCountry     = "country"     // an ISO 3166 country, also sent as its code in <name>Code
+)
+
+// FormField - a question a pool asks in its application form
+type FormField struct {
+	Name      string   `json:"name"`      // key of the answer in the application
+	Label     string   `json:"label"`     // the question
+	Type      string   `json:"type"`      // one of the Field types, text when empty
+	Required  bool     `json:"required"`  // an answer can't be empty
+	Pattern   string   `json:"pattern"`   // regular expression answers to text fields must match
+	Choices   []string `json:"choices"`   // answers of choice and multichoice fields
+	Help      string   `json:"help"`      // shown when the user types ?
+	Sensitive bool     `json:"sensitive"` // never saved in the application draft
+}
+
+// reservedNames - keys of the application the CLI writes itself: the pool
+// and the code of the country of the built-in questions
+var reservedNames = []string{"pool", "locationCode"}
+
+// check - a field the CLI can ask
+func (f FormField) check() error {
+	if f.Name == "" {
+		return fmt.Errorf("a field has no name")
+	}
+	for _, reserved := range reservedNames {
+		if strings.EqualFold(f.Name, reserved) {
+			return fmt.Errorf("field name %q is used by the CLI", f.Name)
+		}
+	}
+
+	switch f.Type {
+	case "", FieldText, FieldNumber, FieldEmail, FieldCountry:
+	case FieldChoice, FieldMultiChoice:
+		if len(f.Choices) == 0 {
+			return fmt.Errorf("field %s has no choices", f.Name)
+		}
+	default:
+		return fmt.Errorf("field %s has unknown type %q", f.Name, f.Type)
+	}
+
+	if f.Pattern != "" {
+		_, err := regexp.Compile(f.Pattern)
+		if err != nil {
+			return fmt.Errorf("field %s: %s", f.Name, err)
+		}
+	}
+	return nil
+}
+
+// GetForm - the application form published by a pool, nil when the pool has
+// none or the Network Gateway is too old to fetch it
+func GetForm(ctx context.Context, poolAddress string) ([]FormField, error) {
+	if !Implements(ctx, "/api/node/applications/<pool>/form") {
+		log.WithFields(log.Fields{"file": "form.go", "func": "GetForm"}).Info("Not fetching the application form, the Network Gateway is too old")
+		return nil, nil
+	}
+
+	url := fmt.Sprintf("http://localhost:%d/api/node/applications/%s/form", config.GetInt("Ports.NetworkGateway"), poolAddress)
+
+	log.WithFields(log.Fields{"file": "form.go", "func": "GetForm"}).Debug("GET: ", url)
+	res, err := utils.SendRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, utils.HandleError(err, "", "node.GetForm")
+	}
+
+	_, err = utils.ControlDaemonHandler([]byte(res))
+	if err != nil {
+		return nil, utils.HandleError(err, "", "node.GetForm")
+	}
+
+	var body struct {
+		Response struct {
+			Fields []FormField `json:"fields"`
+		} `json:"response"`
+	}
+	err = json.Unmarshal([]byte(res), &body)
+	if err != nil {
+		return nil, utils.HandleError(err, "Invalid server response", "node.GetForm")
+	}
+
+	err = checkForm(body.Response.Fields)
+	if err != nil {
+		return nil, utils.HandleError(err, "The application form of this pool is invalid ("+err.Error()+"), please tell the pool operator", "node.GetForm")
+	}
+
+	return body.Response.Fields, nil
+}
+
+// checkForm - every field can be asked, and every answer has a key of its
+// own: names are unique, and no name is the <name>Code the code of a country
+// field is sent in. Names are compared ignoring case.
+func checkForm(fields []FormField) error {
+	owners := make(map[string]string) // lower case key -> the field sending it
+	claim := func(key, field string) error {
+		if owner, ok := owners[strings.ToLower(key)]; ok {
+			if strings.EqualFold(owner, field) && strings.EqualFold(key, field) {
+				return fmt.Errorf("field %s is in the form twice", field)
+			}
+			return fmt.Errorf("fields %s and %s both send %s", owner, field, key)
+		}
+		owners[strings.ToLower(key)] = field
+		return nil
+	}
+
+	for _, field := range fields {
+		err := field.check()
+		if err == nil {
+			err = claim(field.Name, field.Name)
+		}
+		if err == nil && field.Type == FieldCountry {
+			err = claim(field.Name+"Code", field.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
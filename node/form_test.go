@@ -0,0 +1,42 @@
+package node
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckForm(t *testing.T) {
+	country := FormField{Name: "country", Type: FieldCountry}
+	tests := []struct {
+		name   string
+		fields []FormField
+		err    string // part of the error, none when empty
+	}{
+		{"valid", []FormField{{Name: "name"}, country, {Name: "speed", Type: FieldChoice, Choices: []string{"fast"}}}, ""},
+		{"no fields", nil, ""},
+		{"no name", []FormField{{Label: "Name?"}}, "no name"},
+		{"pool", []FormField{{Name: "pool"}}, "used by the CLI"},
+		{"pool in capitals", []FormField{{Name: "Pool"}}, "used by the CLI"},
+		{"built-in country code", []FormField{{Name: "locationCode"}}, "used by the CLI"},
+		{"twice", []FormField{{Name: "name"}, {Name: "name"}}, "twice"},
+		{"twice in another case", []FormField{{Name: "name"}, {Name: "Name"}}, "twice"},
+		{"country code after", []FormField{country, {Name: "countryCode"}}, "both send countryCode"},
+		{"country code before", []FormField{{Name: "countrycode"}, country}, "both send countryCode"},
+		{"code of a text field", []FormField{{Name: "bio"}, {Name: "bioCode"}}, ""},
+		{"unknown type", []FormField{{Name: "name", Type: "date"}}, "unknown type"},
+		{"no choices", []FormField{{Name: "speed", Type: FieldMultiChoice}}, "no choices"},
+		{"bad pattern", []FormField{{Name: "name", Pattern: "("}}, "field name"},
+	}
+
+	for _, test := range tests {
+		err := checkForm(test.fields)
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%s: %s", test.name, err)
+		case test.err != "" && err == nil:
+			t.Errorf("%s: no error, want %q", test.name, test.err)
+		case err != nil && !strings.Contains(err.Error(), test.err):
+			t.Errorf("%s: error %q, want %q", test.name, err, test.err)
+		}
+	}
+}
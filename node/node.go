@@ -1,21 +1,23 @@
 package node
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/gladiusio/gladius-cli/config"
 	"github.com/gladiusio/gladius-cli/utils"
 	log "github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
 )
 
 // GetApplication - get node application from pool
-func GetApplication(poolAddress string) (map[string]interface{}, error) {
-	url := fmt.Sprintf("http://localhost:%d/api/node/applications/%s/view", viper.GetInt("Ports.NetworkGateway"), poolAddress)
+func GetApplication(ctx context.Context, poolAddress string) (map[string]interface{}, error) {
+	url := fmt.Sprintf("http://localhost:%d/api/node/applications/%s/view", config.GetInt("Ports.NetworkGateway"), poolAddress)
 
 	log.WithFields(log.Fields{"file": "node.go", "func": "GetApplication"}).Debug("GET: ", url)
-	res, err := utils.SendRequest("GET", url, nil)
+	res, err := utils.SendRequest(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, utils.HandleError(err, "", "node.GetNodeData")
 	}
@@ -33,30 +35,30 @@ func GetApplication(poolAddress string) (map[string]interface{}, error) {
 	return data, nil //node data
 }
 
-// ApplyToPool - apply to a pool
-func ApplyToPool(poolAddress string, data map[string]interface{}) (string, error) {
-	url := fmt.Sprintf("http://localhost:%d/api/node/applications/%s/new", viper.GetInt("Ports.NetworkGateway"), poolAddress)
+// ApplyToPool - apply to a pool, returns the hash of the transaction
+func ApplyToPool(ctx context.Context, poolAddress string, data map[string]interface{}) (string, error) {
+	url := fmt.Sprintf("http://localhost:%d/api/node/applications/%s/new", config.GetInt("Ports.NetworkGateway"), poolAddress)
 
 	log.WithFields(log.Fields{"file": "node.go", "func": "ApplyToPool"}).Debug("POST: ", url)
-	res, err := utils.SendRequest("POST", url, data)
+	res, err := utils.SendRequest(ctx, "POST", url, data)
 	if err != nil {
 		return "", utils.HandleError(err, "", "node.AppyToPool")
 	}
 
 	log.WithFields(log.Fields{"file": "node.go", "func": "ApplyToPool"}).Debug("Response recieved, piping through the response handler")
-	_, err = utils.ControlDaemonHandler([]byte(res))
+	api, err := utils.ControlDaemonHandler([]byte(res))
 	if err != nil {
 		return "", utils.HandleError(err, "", "node.AppyToPool")
 	}
 
 	log.WithFields(log.Fields{"file": "node.go", "func": "ApplyToPool"}).Debug("Decoding response fields")
 
-	return "success", nil //tx hash
+	return api.TxHashString(), nil //tx hash, "" if the application didn't need one
 }
 
 // CheckPoolApplication - check the status of your pool application
-func CheckPoolApplication(poolAddress string) (string, error) {
-	application, err := GetApplication(poolAddress)
+func CheckPoolApplication(ctx context.Context, poolAddress string) (string, error) {
+	application, err := GetApplication(ctx, poolAddress)
 	if err != nil {
 		return "", utils.HandleError(err, "", "node.CheckPoolApplication")
 	}
@@ -80,9 +82,9 @@ func CheckPoolApplication(poolAddress string) (string, error) {
 }
 
 // Start - start network gateway and edged
-func Start() (string, error) {
-	timeoutURL := fmt.Sprintf("http://localhost:%d/service/set_timeout", viper.GetInt("Ports.Guardian"))
-	startURL := fmt.Sprintf("http://localhost:%d/service/set_state/all", viper.GetInt("Ports.Guardian"))
+func Start(ctx context.Context) (string, error) {
+	timeoutURL := fmt.Sprintf("http://localhost:%d/service/set_timeout", config.GetInt("Ports.Guardian"))
+	startURL := fmt.Sprintf("http://localhost:%d/service/set_state/all", config.GetInt("Ports.Guardian"))
 
 	timeout := make(map[string]int)
 	timeout["timeout"] = 3
@@ -91,13 +93,13 @@ func Start() (string, error) {
 	running["running"] = true
 
 	log.WithFields(log.Fields{"file": "node.go", "func": "Start"}).Debug("POST: ", timeoutURL)
-	_, err := utils.SendRequest("POST", timeoutURL, timeout)
+	_, err := utils.SendRequest(ctx, "POST", timeoutURL, timeout)
 	if err != nil {
 		return "Failed to set timeout", utils.HandleError(err, "", "node.Start")
 	}
 
 	log.WithFields(log.Fields{"file": "node.go", "func": "Start"}).Debug("POST: ", startURL)
-	_, err = utils.SendRequest("PUT", startURL, running)
+	_, err = utils.SendRequest(ctx, "PUT", startURL, running)
 	if err != nil {
 		return "Failed to star one or more modules", utils.HandleError(err, "", "node.Start")
 	}
@@ -106,14 +108,14 @@ func Start() (string, error) {
 }
 
 // Stop - stop network gateway and edged
-func Stop() (string, error) {
-	stopURL := fmt.Sprintf("http://localhost:%d/service/set_state/all", viper.GetInt("Ports.Guardian"))
+func Stop(ctx context.Context) (string, error) {
+	stopURL := fmt.Sprintf("http://localhost:%d/service/set_state/all", config.GetInt("Ports.Guardian"))
 
 	running := make(map[string]bool)
 	running["running"] = false
 
 	log.WithFields(log.Fields{"file": "node.go", "func": "Start"}).Debug("POST: ", stopURL)
-	_, err := utils.SendRequest("PUT", stopURL, running)
+	_, err := utils.SendRequest(ctx, "PUT", stopURL, running)
 	if err != nil {
 		return "Failed to stop one or both modules", utils.HandleError(err, "", "node.Start")
 	}
@@ -122,23 +124,13 @@ func Stop() (string, error) {
 }
 
 // GetVersion - get individual version number from module
-func GetVersion(module string) (string, error) {
-	var port int
-	switch module {
-	case "guardian":
-		port = viper.GetInt("Ports.Guardian")
-	case "edged":
-		port = viper.GetInt("Ports.EdgeD")
-	case "network-gateway":
-		port = viper.GetInt("Ports.NetworkGateway")
-	default:
-		port = 0
-	}
-
-	if port == 0 {
-		return "", fmt.Errorf("Module %s not found", module)
-	}
-	res, err := utils.SendRequest("GET", fmt.Sprintf("http://localhost:%d/version", port), nil)
+func GetVersion(ctx context.Context, module string) (string, error) {
+	port, err := config.ModulePort(module)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := utils.SendRequest(ctx, "GET", fmt.Sprintf("http://localhost:%d/version", port), nil)
 	if err != nil {
 		return "", err
 	}
@@ -149,51 +141,34 @@ func GetVersion(module string) (string, error) {
 		return "", err
 	}
 
-	res1 := response["response"].(map[string]interface{})
-	version := res1["version"].(string)
+	res1, _ := response["response"].(map[string]interface{})
+	version, ok := res1["version"].(string)
+	if !ok {
+		return "", fmt.Errorf("Module %s did not report a version", module)
+	}
 
 	return version, nil
 }
 
-func NeedUpdate() (bool, error) {
-	// get the official versions
-	res, err := utils.SendRequest("GET", "https://gladius-version.nyc3.digitaloceanspaces.com/version.json", nil)
-	if err != nil {
-		return false, err
-	}
-
-	// parse response
-	var response = make(map[string]interface{})
-	err = json.Unmarshal([]byte(res), &response)
+// NeedUpdate - compare the running modules with the official versions,
+// using a manifest fetched less than maxAge ago if there is one
+func NeedUpdate(ctx context.Context, maxAge time.Duration) (bool, error) {
+	officialVersions, err := OfficialVersions(ctx, maxAge)
 	if err != nil {
-		return false, err
+		return false, utils.HandleError(err, "Could not check for updates", "node.NeedUpdate")
 	}
 
-	officialVersions := response
-
-	// get the current versions
-	currentVersion := make(map[string]string)
-	currentVersion["gladius-guardian"], _ = GetVersion("guardian")
-	currentVersion["gladius-edged"], _ = GetVersion("edged")
-	currentVersion["gladius-network-gateway"], _ = GetVersion("network-gateway")
-
-	var needUpdate [3]bool
-
-	// compare
-	count := 0
-	for ver := range officialVersions {
-		if officialVersions[ver] != currentVersion[ver] {
-			needUpdate[count] = false
-		} else {
-			needUpdate[count] = true
+	// compare with the current versions, a module that is offline has no
+	// version to compare
+	for _, status := range ProbeModules(ctx) {
+		if !status.Online {
+			continue
+		}
+		official, ok := officialVersions[status.Module.Release]
+		if ok && official != status.Version {
+			return true, utils.HandleError(errors.New("One or more of your modules is out of date"), "One or more of your modules is out of date", "node.needUpdate")
 		}
-		count++
 	}
 
-	// if anything here is false then we need an update
-	if needUpdate[0] && needUpdate[1] && needUpdate[2] {
-		return false, nil
-	} else {
-		return true, utils.HandleError(errors.New("One or more of your modules is out of date"), "One or more of your modules is out of date", "node.needUpdate")
-	}
+	return false, nil
 }
@@ -0,0 +1,96 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gladiusio/gladius-cli/config"
+	"github.com/gladiusio/gladius-cli/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// Statuses of an application to a pool
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+// PoolApplication - an application received by a pool run by the node's
+// account
+type PoolApplication struct {
+	Node     string                 `json:"node"`    // address of the node applying
+	Profile  map[string]interface{} `json:"profile"` // the answers of the node
+	Pending  bool                   `json:"pending"`
+	Approved bool                   `json:"approved"`
+	Message  string                 `json:"message"` // sent to the node with the decision
+}
+
+// Status - one of the Status constants
+func (a PoolApplication) Status() string {
+	switch {
+	case a.Pending:
+		return StatusPending
+	case a.Approved:
+		return StatusApproved
+	}
+	return StatusRejected
+}
+
+// GetPoolApplications - every application received by a pool
+func GetPoolApplications(ctx context.Context, poolAddress string) ([]PoolApplication, error) {
+	url := fmt.Sprintf("http://localhost:%d/api/pool/applications/%s/list", config.GetInt("Ports.NetworkGateway"), poolAddress)
+
+	log.WithFields(log.Fields{"file": "pool.go", "func": "GetPoolApplications"}).Debug("GET: ", url)
+	res, err := utils.SendRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, utils.HandleError(err, "", "node.GetPoolApplications")
+	}
+
+	_, err = utils.ControlDaemonHandler([]byte(res))
+	if err != nil {
+		return nil, utils.HandleError(err, "", "node.GetPoolApplications")
+	}
+
+	var body struct {
+		Response struct {
+			Applications []PoolApplication `json:"applications"`
+		} `json:"response"`
+	}
+	err = json.Unmarshal([]byte(res), &body)
+	if err != nil {
+		return nil, utils.HandleError(err, "Invalid server response", "node.GetPoolApplications")
+	}
+
+	applications := body.Response.Applications
+	for i := range applications {
+		applications[i].Node = utils.ChecksumAddress(applications[i].Node)
+	}
+	return applications, nil
+}
+
+// DecideApplication - approve or reject the application of a node to a pool,
+// with a message for the node ("" for none). Returns the hash of the
+// transaction, "" if the decision didn't need one. A locked wallet is
+// unlocked by asking for the passphrase.
+func DecideApplication(ctx context.Context, poolAddress, nodeAddress string, approve bool, message string) (string, error) {
+	action := "reject"
+	if approve {
+		action = "approve"
+	}
+	url := fmt.Sprintf("http://localhost:%d/api/pool/applications/%s/%s/%s", config.GetInt("Ports.NetworkGateway"), poolAddress, nodeAddress, action)
+
+	log.WithFields(log.Fields{"file": "pool.go", "func": "DecideApplication"}).Debug("POST: ", url)
+	res, err := utils.SendRequest(ctx, "POST", url, map[string]string{"message": message})
+	if err != nil {
+		return "", utils.HandleError(err, "", "node.DecideApplication")
+	}
+
+	api, err := utils.ControlDaemonHandler([]byte(res))
+	if err != nil {
+		return "", utils.HandleError(err, "", "node.DecideApplication")
+	}
+
+	return api.TxHashString(), nil
+}
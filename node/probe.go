@@ -0,0 +1,93 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gladiusio/gladius-cli/config"
+	"github.com/gladiusio/gladius-cli/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// ModuleStatus - result of probing a module
+type ModuleStatus struct {
+	Module  config.Module
+	Online  bool
+	Version string
+	Latency time.Duration
+	Err     error
+}
+
+type probe struct {
+	once   sync.Once
+	status ModuleStatus
+}
+
+var (
+	probesMu sync.Mutex
+	probes   = make(map[string]*probe)
+)
+
+// ProbeModules - get the version of every module, querying them in parallel.
+// Each module is only queried once, later calls return the same result.
+func ProbeModules(ctx context.Context) []ModuleStatus {
+	statuses := make([]ModuleStatus, len(config.Modules))
+
+	var wg sync.WaitGroup
+	for i, module := range config.Modules {
+		wg.Add(1)
+		go func(i int, module config.Module) {
+			defer wg.Done()
+			statuses[i] = probeModule(ctx, module)
+		}(i, module)
+	}
+	wg.Wait()
+
+	return statuses
+}
+
+// ProbeModule - cached status of a single module
+func ProbeModule(ctx context.Context, name string) (ModuleStatus, bool) {
+	for _, module := range config.Modules {
+		if module.Name == name {
+			return probeModule(ctx, module), true
+		}
+	}
+
+	return ModuleStatus{}, false
+}
+
+// ForgetProbes - query the modules again next time, after starting or
+// stopping them
+func ForgetProbes() {
+	probesMu.Lock()
+	defer probesMu.Unlock()
+	probes = make(map[string]*probe)
+}
+
+func probeModule(ctx context.Context, module config.Module) ModuleStatus {
+	probesMu.Lock()
+	p, ok := probes[module.Name]
+	if !ok {
+		p = &probe{}
+		probes[module.Name] = p
+	}
+	probesMu.Unlock()
+
+	p.once.Do(func() {
+		started := utils.Now()
+		version, err := GetVersion(ctx, module.Name)
+		p.status = ModuleStatus{
+			Module:  module,
+			Online:  err == nil,
+			Version: version,
+			Latency: utils.Now().Sub(started),
+			Err:     err,
+		}
+
+		log.WithFields(log.Fields{"file": "probe.go", "func": "probeModule", "module": module.Name, "latency": p.status.Latency.String()}).Debug("Probed module, online: ", err == nil)
+	})
+
+	return p.status
+}
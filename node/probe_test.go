@@ -0,0 +1,156 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gladiusio/gladius-cli/config"
+	"github.com/gladiusio/gladius-cli/utils"
+)
+
+// doerFunc - a client answering with a function
+type doerFunc func(req *http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func answer(body string) *http.Response {
+	return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}
+}
+
+// fakeModules - the modules answer /version with the versions, keyed by
+// module name, the others are offline. The official versions are served
+// from manifest, an error when it is empty. Every request is counted. The
+// modules are probed in parallel, mu guards the fields.
+type fakeModules struct {
+	mu       sync.Mutex
+	versions map[string]string
+	manifest string
+	requests map[string]int
+}
+
+// useFakeModules - a context sending its requests to the fake modules, the
+// base dir and probes are the test's own
+func useFakeModules(t *testing.T, modules *fakeModules) context.Context {
+	config.SetBaseDir(t.TempDir())
+	config.CLIDefaults()
+	ForgetProbes()
+	t.Cleanup(func() {
+		config.SetBaseDir("")
+		ForgetProbes()
+	})
+
+	modules.requests = make(map[string]int)
+	return utils.WithClient(context.Background(), doerFunc(modules.do))
+}
+
+func (m *fakeModules) do(req *http.Request) (*http.Response, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if req.URL.String() == VersionManifestURL {
+		m.requests["manifest"]++
+		if m.manifest == "" {
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+		}
+		return answer(m.manifest), nil
+	}
+
+	for _, module := range config.Modules {
+		if fmt.Sprint(config.GetInt(module.PortKey)) != req.URL.Port() {
+			continue
+		}
+		m.requests[module.Name]++
+		if version, ok := m.versions[module.Name]; ok {
+			return answer(fmt.Sprintf(`{"response": {"version": "%s"}}`, version)), nil
+		}
+	}
+	return nil, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+}
+
+func TestProbeModules(t *testing.T) {
+	modules := &fakeModules{versions: map[string]string{"guardian": "0.8.0", "network-gateway": "0.7.1"}}
+	ctx := useFakeModules(t, modules)
+
+	statuses := ProbeModules(ctx)
+	if len(statuses) != len(config.Modules) {
+		t.Fatalf("%d statuses, want one per module", len(statuses))
+	}
+	want := map[string]string{"guardian": "0.8.0", "edged": "", "network-gateway": "0.7.1"}
+	for i, status := range statuses {
+		if status.Module != config.Modules[i] {
+			t.Errorf("status %d is of %s, want %s", i, status.Module.Name, config.Modules[i].Name)
+		}
+		version := want[status.Module.Name]
+		if status.Online != (version != "") || status.Version != version {
+			t.Errorf("%s: online %v version %q, want %q", status.Module.Name, status.Online, status.Version, version)
+		}
+		if status.Online == (status.Err != nil) {
+			t.Errorf("%s: online %v with error %v", status.Module.Name, status.Online, status.Err)
+		}
+	}
+
+	// every module is asked once
+	ProbeModules(ctx)
+	if status, ok := ProbeModule(ctx, "guardian"); !ok || status.Version != "0.8.0" {
+		t.Errorf("ProbeModule(guardian) = %+v, %v", status, ok)
+	}
+	for _, module := range config.Modules {
+		if n := modules.requests[module.Name]; n != 1 {
+			t.Errorf("%s asked %d times, want once", module.Name, n)
+		}
+	}
+
+	// until the probes are forgotten
+	modules.versions["edged"] = "0.8.2"
+	ForgetProbes()
+	if status, _ := ProbeModule(ctx, "edged"); !status.Online || status.Version != "0.8.2" {
+		t.Errorf("edged after forgetting: %+v", status)
+	}
+
+	if _, ok := ProbeModule(ctx, "controld"); ok {
+		t.Error("probed a module that doesn't exist")
+	}
+}
+
+func TestNeedUpdate(t *testing.T) {
+	manifest := `{"gladius-guardian": "0.8.0", "gladius-edged": "0.8.2", "gladius-network-gateway": "0.7.1"}`
+	tests := []struct {
+		name     string
+		versions map[string]string
+		update   bool
+	}{
+		{"up to date", map[string]string{"guardian": "0.8.0", "edged": "0.8.2", "network-gateway": "0.7.1"}, false},
+		{"one behind", map[string]string{"guardian": "0.8.0", "edged": "0.8.1", "network-gateway": "0.7.1"}, true},
+		{"offline modules", map[string]string{"guardian": "0.8.0"}, false},
+		{"offline and one behind", map[string]string{"network-gateway": "0.7.0"}, true},
+		{"all offline", nil, false},
+	}
+
+	for _, test := range tests {
+		ctx := useFakeModules(t, &fakeModules{versions: test.versions, manifest: manifest})
+		update, err := NeedUpdate(ctx, 0)
+		if update != test.update {
+			t.Errorf("%s: NeedUpdate = %v, %v, want %v", test.name, update, err, test.update)
+		}
+		if !update && err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		}
+	}
+
+	// no manifest to compare with
+	ctx := useFakeModules(t, &fakeModules{versions: map[string]string{"guardian": "0.1.0"}})
+	update, err := NeedUpdate(ctx, time.Hour)
+	if update || err == nil {
+		t.Errorf("without a manifest: %v, %v, want an error", update, err)
+	}
+}
@@ -0,0 +1,105 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/gladiusio/gladius-cli/config"
+	"github.com/gladiusio/gladius-cli/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// TransferEstimate - what sending a transfer costs
+type TransferEstimate struct {
+	Gas      *big.Int // gas the transaction uses
+	GasPrice *big.Int // wei per gas
+}
+
+// Fee - the most the transaction costs, in wei
+func (e TransferEstimate) Fee() *big.Int {
+	return new(big.Int).Mul(e.Gas, e.GasPrice)
+}
+
+// FindToken - the token with this symbol, in any case
+func FindToken(symbol string) (Token, bool) {
+	for _, token := range Tokens {
+		if strings.EqualFold(token.Symbol, symbol) {
+			return token, true
+		}
+	}
+	return Token{}, false
+}
+
+// transferBody - a transfer of amount (in the smallest unit of token) to
+func transferBody(to string, token Token, amount *big.Int) map[string]interface{} {
+	return map[string]interface{}{
+		"to":     to,
+		"symbol": strings.ToLower(token.Symbol),
+		"value":  amount.String(),
+	}
+}
+
+// EstimateTransfer - gas needed to send amount of token to an address
+func EstimateTransfer(ctx context.Context, to string, token Token, amount *big.Int) (TransferEstimate, error) {
+	url := fmt.Sprintf("http://localhost:%d/api/keystore/transaction/estimate", config.GetInt("Ports.NetworkGateway"))
+
+	log.WithFields(log.Fields{"file": "transfer.go", "func": "EstimateTransfer"}).Debug("POST: ", url)
+	res, err := utils.SendRequest(ctx, "POST", url, transferBody(to, token, amount))
+	if err != nil {
+		return TransferEstimate{}, utils.HandleError(err, "", "node.EstimateTransfer")
+	}
+
+	_, err = utils.ControlDaemonHandler([]byte(res))
+	if err != nil {
+		return TransferEstimate{}, utils.HandleError(err, "", "node.EstimateTransfer")
+	}
+
+	// decoded again as a float64 can't hold every amount in wei
+	var body struct {
+		Response struct {
+			Gas      json.RawMessage `json:"gas"`
+			GasPrice json.RawMessage `json:"gasPrice"`
+		} `json:"response"`
+	}
+	err = json.Unmarshal([]byte(res), &body)
+	if err != nil {
+		return TransferEstimate{}, utils.HandleError(err, "Invalid server response", "node.EstimateTransfer")
+	}
+
+	gas, gasOK := utils.ParseAmount(strings.Trim(string(body.Response.Gas), `"`))
+	gasPrice, priceOK := utils.ParseAmount(strings.Trim(string(body.Response.GasPrice), `"`))
+	if !gasOK || !priceOK {
+		return TransferEstimate{}, utils.HandleError(errors.New("invalid gas estimate "+res), "Invalid server response", "node.EstimateTransfer")
+	}
+
+	return TransferEstimate{Gas: gas, GasPrice: gasPrice}, nil
+}
+
+// Transfer - send amount of token from the node's account, returns the hash
+// of the transaction. A locked wallet is unlocked by asking for the
+// passphrase.
+func Transfer(ctx context.Context, to string, token Token, amount *big.Int) (string, error) {
+	url := fmt.Sprintf("http://localhost:%d/api/keystore/transaction/send", config.GetInt("Ports.NetworkGateway"))
+
+	log.WithFields(log.Fields{"file": "transfer.go", "func": "Transfer"}).Debug("POST: ", url)
+	res, err := utils.SendRequest(ctx, "POST", url, transferBody(to, token, amount))
+	if err != nil {
+		return "", utils.HandleError(err, "", "node.Transfer")
+	}
+
+	api, err := utils.ControlDaemonHandler([]byte(res))
+	if err != nil {
+		return "", utils.HandleError(err, "", "node.Transfer")
+	}
+
+	hash := api.TxHashString()
+	if hash == "" {
+		return "", utils.HandleError(errors.New("no tx hash in response"), "The Network Gateway did not return a transaction", "node.Transfer")
+	}
+
+	return hash, nil
+}
@@ -0,0 +1,95 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gladiusio/gladius-cli/config"
+	"github.com/gladiusio/gladius-cli/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// VersionManifestURL - where the official module versions are published
+const VersionManifestURL = "https://gladius-version.nyc3.digitaloceanspaces.com/version.json"
+
+// versionCache - the manifest as stored in the Gladius base dir
+type versionCache struct {
+	FetchedAt time.Time         `json:"fetchedAt"`
+	Versions  map[string]string `json:"versions"`
+}
+
+// VersionCachePath - where the official version list is cached
+func VersionCachePath() (string, error) {
+	base, err := config.GetGladiusBase()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "version-cache.json"), nil
+}
+
+// OfficialVersions - the official version of each module, keyed by release
+// name. A cached manifest younger than maxAge is used instead of fetching
+// it, and a stale one if fetching fails.
+func OfficialVersions(ctx context.Context, maxAge time.Duration) (map[string]string, error) {
+	cache, cacheErr := readVersionCache()
+	if cacheErr == nil && maxAge > 0 && utils.Now().Sub(cache.FetchedAt) < maxAge {
+		return cache.Versions, nil
+	}
+
+	res, err := utils.SendRequest(ctx, "GET", VersionManifestURL, nil)
+	if err == nil {
+		versions := make(map[string]string)
+		err = json.Unmarshal([]byte(res), &versions)
+		if err == nil {
+			writeVersionCache(versionCache{FetchedAt: utils.Now(), Versions: versions})
+			return versions, nil
+		}
+	}
+
+	if cacheErr == nil {
+		log.WithFields(log.Fields{"file": "update.go", "func": "OfficialVersions"}).Debug("Could not fetch version manifest, using the cached one: ", err)
+		return cache.Versions, nil
+	}
+
+	return nil, err
+}
+
+func readVersionCache() (versionCache, error) {
+	var cache versionCache
+
+	path, err := VersionCachePath()
+	if err != nil {
+		return cache, err
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cache, err
+	}
+
+	err = json.Unmarshal(b, &cache)
+	return cache, err
+}
+
+func writeVersionCache(cache versionCache) {
+	path, err := VersionCachePath()
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	}
+
+	var b []byte
+	if err == nil {
+		b, err = json.Marshal(cache)
+	}
+	if err == nil {
+		err = ioutil.WriteFile(path, b, 0644)
+	}
+
+	if err != nil {
+		log.WithFields(log.Fields{"file": "update.go", "func": "writeVersionCache"}).Warning("Could not cache version manifest: ", err)
+	}
+}
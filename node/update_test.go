@@ -0,0 +1,82 @@
+package node
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/gladiusio/gladius-cli/utils"
+)
+
+// useClock - utils.Now returns *now until the test ends
+func useClock(t *testing.T, now *time.Time) {
+	previous := utils.Now
+	utils.Now = func() time.Time { return *now }
+	t.Cleanup(func() { utils.Now = previous })
+}
+
+func TestOfficialVersionsCache(t *testing.T) {
+	now := time.Date(2018, 8, 20, 18, 0, 0, 0, time.UTC)
+	useClock(t, &now)
+	modules := &fakeModules{manifest: `{"gladius-guardian": "0.8.0"}`}
+	ctx := useFakeModules(t, modules)
+
+	fetch := func(maxAge time.Duration) string {
+		t.Helper()
+		versions, err := OfficialVersions(ctx, maxAge)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return versions["gladius-guardian"]
+	}
+
+	if version := fetch(24 * time.Hour); version != "0.8.0" || modules.requests["manifest"] != 1 {
+		t.Fatalf("without a cache: %q after %d fetches", version, modules.requests["manifest"])
+	}
+	path, err := VersionCachePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ioutil.ReadFile(path); err != nil {
+		t.Fatalf("the manifest wasn't cached: %v", err)
+	}
+
+	// the cache is used until it is maxAge old
+	modules.manifest = `{"gladius-guardian": "0.9.0"}`
+	now = now.Add(23 * time.Hour)
+	if version := fetch(24 * time.Hour); version != "0.8.0" || modules.requests["manifest"] != 1 {
+		t.Errorf("cache 23h old: %q after %d fetches, want the cached 0.8.0", version, modules.requests["manifest"])
+	}
+	now = now.Add(time.Hour)
+	if version := fetch(24 * time.Hour); version != "0.9.0" || modules.requests["manifest"] != 2 {
+		t.Errorf("cache a day old: %q after %d fetches, want 0.9.0 fetched", version, modules.requests["manifest"])
+	}
+
+	// a max age of 0 always fetches
+	modules.manifest = `{"gladius-guardian": "1.0.0"}`
+	if version := fetch(0); version != "1.0.0" || modules.requests["manifest"] != 3 {
+		t.Errorf("max age 0: %q after %d fetches", version, modules.requests["manifest"])
+	}
+
+	// a stale cache beats no manifest at all
+	now = now.Add(48 * time.Hour)
+	for _, manifest := range []string{"", "<html>not found</html>"} {
+		modules.manifest = manifest
+		if version := fetch(24 * time.Hour); version != "1.0.0" {
+			t.Errorf("manifest %q: %q, want the stale 1.0.0", manifest, version)
+		}
+	}
+}
+
+func TestOfficialVersionsOffline(t *testing.T) {
+	ctx := useFakeModules(t, &fakeModules{})
+	versions, err := OfficialVersions(ctx, 24*time.Hour)
+	if err == nil {
+		t.Errorf("offline without a cache: %v, want an error", versions)
+	}
+
+	path, _ := VersionCachePath()
+	if _, err := ioutil.ReadFile(path); err == nil {
+		t.Error("a failed fetch was cached")
+	}
+}
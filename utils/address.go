@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// ChecksumAddress - the EIP-55 mixed case form of an Ethereum address
+func ChecksumAddress(address string) string {
+	lower := strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X"))
+
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write([]byte(lower))
+	digest := hex.EncodeToString(hash.Sum(nil))
+
+	checksummed := []byte(lower)
+	for i, c := range checksummed {
+		// letters are upper case where the nibble of the hash is 8 or more
+		if c >= 'a' && c <= 'f' && digest[i] >= '8' {
+			checksummed[i] = c - 'a' + 'A'
+		}
+	}
+
+	return "0x" + string(checksummed)
+}
+
+// Address - an Ethereum address in its EIP-55 checksummed form
+type Address string
+
+var addressPattern = regexp.MustCompile("^0x[a-fA-F0-9]{40}$")
+
+// ErrAddressChecksum - a mixed case address whose checksum doesn't match,
+// most likely a typo
+var ErrAddressChecksum = errors.New("The address has a wrong checksum, check it for typos")
+
+// ParseAddress - validate an address typed by the user and normalise it.
+// Mixed case input must carry a valid EIP-55 checksum. All lower (or upper)
+// case input has no checksum to check, unchecked is true so the caller can
+// warn about it.
+func ParseAddress(s string) (address Address, unchecked bool, err error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", false, errors.New("This is a required field")
+	}
+	if !addressPattern.MatchString(s) {
+		return "", false, errors.New("Please enter a valid ethereum address (0x followed by 40 hex digits)")
+	}
+
+	checksummed := ChecksumAddress(s)
+	digits := s[2:]
+	if digits == strings.ToLower(digits) || digits == strings.ToUpper(digits) {
+		return Address(checksummed), true, nil
+	}
+	if s != checksummed {
+		return "", false, ErrAddressChecksum
+	}
+	return Address(checksummed), false, nil
+}
+
+// String - the checksummed address
+func (a Address) String() string {
+	return string(a)
+}
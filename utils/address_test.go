@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+// the test vectors of EIP-55
+var eip55Addresses = []string{
+	// all caps
+	"0x52908400098527886E0F7030069857D2E4169EE7",
+	"0x8617E340B3D01FA5F11F306F4090FD50E238070D",
+	// all lower
+	"0xde709f2102306220921060314715629080e2fb77",
+	"0x27b1fdb04752bbc536007a920d24acb045561c26",
+	// mixed
+	"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+	"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+	"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+	"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+}
+
+func TestChecksumAddress(t *testing.T) {
+	for _, want := range eip55Addresses {
+		for _, input := range []string{want, strings.ToLower(want), "0x" + strings.ToUpper(want[2:]), "0X" + want[2:], want[2:]} {
+			if got := ChecksumAddress(input); got != want {
+				t.Errorf("ChecksumAddress(%s) = %s, want %s", input, got, want)
+			}
+		}
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	for _, want := range eip55Addresses {
+		digits := want[2:]
+		mixed := digits != strings.ToLower(digits) && digits != strings.ToUpper(digits)
+
+		// a checksummed address is checked, so nothing to warn about
+		address, unchecked, err := ParseAddress(" " + want + "\n")
+		if err != nil || address.String() != want || unchecked != !mixed {
+			t.Errorf("ParseAddress(%s) = %s, %v, %v, want it checked", want, address, unchecked, err)
+		}
+
+		// all lower or upper case has no checksum, the caller warns
+		for _, input := range []string{strings.ToLower(want), "0x" + strings.ToUpper(digits)} {
+			address, unchecked, err := ParseAddress(input)
+			if err != nil || address.String() != want || !unchecked {
+				t.Errorf("ParseAddress(%s) = %s, %v, %v, want %s unchecked", input, address, unchecked, err, want)
+			}
+		}
+	}
+}
+
+func TestParseAddressBadChecksum(t *testing.T) {
+	tests := []string{
+		// one letter in the wrong case
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD",
+		"0x5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		// a digit mistyped
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAee",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d358",
+		// the case of another address
+		"0xdBf03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+	}
+
+	for _, input := range tests {
+		address, _, err := ParseAddress(input)
+		if err != ErrAddressChecksum {
+			t.Errorf("ParseAddress(%s) = %s, %v, want ErrAddressChecksum", input, address, err)
+		}
+	}
+}
+
+func TestParseAddressInvalid(t *testing.T) {
+	tests := []struct {
+		input, err string
+	}{
+		{"", "required"},
+		{"   ", "required"},
+		{"0x1234", "40 hex digits"},
+		{"5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "40 hex digits"},
+		{"0X5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "40 hex digits"},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAe", "40 hex digits"},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed0", "40 hex digits"},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeg", "40 hex digits"},
+		{"0x5aAeb6053F3E94C9 9A09f33669435E7Ef1BeAed", "40 hex digits"},
+	}
+
+	for _, test := range tests {
+		address, _, err := ParseAddress(test.input)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("ParseAddress(%q) = %s, %v, want %q", test.input, address, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package utils
+
+// Countries - ISO 3166-1 countries, from the iso_3166-1.json of Debian's
+// iso-codes with common names (USA, UK, Holland...) added by hand
+var Countries = []Country{
+	{Code: "AD", Code3: "AND", Name: "Andorra", Aliases: []string{"Principality of Andorra"}},
+	{Code: "AE", Code3: "ARE", Name: "United Arab Emirates", Aliases: []string{"UAE", "Emirates"}},
+	{Code: "AF", Code3: "AFG", Name: "Afghanistan", Aliases: []string{"Islamic Republic of Afghanistan"}},
+	{Code: "AG", Code3: "ATG", Name: "Antigua and Barbuda"},
+	{Code: "AI", Code3: "AIA", Name: "Anguilla"},
+	{Code: "AL", Code3: "ALB", Name: "Albania", Aliases: []string{"Republic of Albania"}},
+	{Code: "AM", Code3: "ARM", Name: "Armenia", Aliases: []string{"Republic of Armenia"}},
+	{Code: "AO", Code3: "AGO", Name: "Angola", Aliases: []string{"Republic of Angola"}},
+	{Code: "AQ", Code3: "ATA", Name: "Antarctica"},
+	{Code: "AR", Code3: "ARG", Name: "Argentina", Aliases: []string{"Argentine Republic"}},
+	{Code: "AS", Code3: "ASM", Name: "American Samoa"},
+	{Code: "AT", Code3: "AUT", Name: "Austria", Aliases: []string{"Republic of Austria"}},
+	{Code: "AU", Code3: "AUS", Name: "Australia"},
+	{Code: "AW", Code3: "ABW", Name: "Aruba"},
+	{Code: "AX", Code3: "ALA", Name: "Åland Islands"},
+	{Code: "AZ", Code3: "AZE", Name: "Azerbaijan", Aliases: []string{"Republic of Azerbaijan"}},
+	{Code: "BA", Code3: "BIH", Name: "Bosnia and Herzegovina", Aliases: []string{"Republic of Bosnia and Herzegovina"}},
+	{Code: "BB", Code3: "BRB", Name: "Barbados"},
+	{Code: "BD", Code3: "BGD", Name: "Bangladesh", Aliases: []string{"People's Republic of Bangladesh"}},
+	{Code: "BE", Code3: "BEL", Name: "Belgium", Aliases: []string{"Kingdom of Belgium"}},
+	{Code: "BF", Code3: "BFA", Name: "Burkina Faso"},
+	{Code: "BG", Code3: "BGR", Name: "Bulgaria", Aliases: []string{"Republic of Bulgaria"}},
+	{Code: "BH", Code3: "BHR", Name: "Bahrain", Aliases: []string{"Kingdom of Bahrain"}},
+	{Code: "BI", Code3: "BDI", Name: "Burundi", Aliases: []string{"Republic of Burundi"}},
+	{Code: "BJ", Code3: "BEN", Name: "Benin", Aliases: []string{"Republic of Benin"}},
+	{Code: "BL", Code3: "BLM", Name: "Saint Barthélemy"},
+	{Code: "BM", Code3: "BMU", Name: "Bermuda"},
+	{Code: "BN", Code3: "BRN", Name: "Brunei Darussalam", Aliases: []string{"Brunei"}},
+	{Code: "BO", Code3: "BOL", Name: "Bolivia", Aliases: []string{"Bolivia, Plurinational State of", "Plurinational State of Bolivia"}},
+	{Code: "BQ", Code3: "BES", Name: "Bonaire, Sint Eustatius and Saba"},
+	{Code: "BR", Code3: "BRA", Name: "Brazil", Aliases: []string{"Federative Republic of Brazil"}},
+	{Code: "BS", Code3: "BHS", Name: "Bahamas", Aliases: []string{"Commonwealth of the Bahamas"}},
+	{Code: "BT", Code3: "BTN", Name: "Bhutan", Aliases: []string{"Kingdom of Bhutan"}},
+	{Code: "BV", Code3: "BVT", Name: "Bouvet Island"},
+	{Code: "BW", Code3: "BWA", Name: "Botswana", Aliases: []string{"Republic of Botswana"}},
+	{Code: "BY", Code3: "BLR", Name: "Belarus", Aliases: []string{"Republic of Belarus"}},
+	{Code: "BZ", Code3: "BLZ", Name: "Belize"},
+	{Code: "CA", Code3: "CAN", Name: "Canada"},
+	{Code: "CC", Code3: "CCK", Name: "Cocos (Keeling) Islands"},
+	{Code: "CD", Code3: "COD", Name: "Congo, The Democratic Republic of the", Aliases: []string{"DR Congo", "DRC", "Congo-Kinshasa"}},
+	{Code: "CF", Code3: "CAF", Name: "Central African Republic"},
+	{Code: "CG", Code3: "COG", Name: "Congo", Aliases: []string{"Republic of the Congo", "Congo-Brazzaville"}},
+	{Code: "CH", Code3: "CHE", Name: "Switzerland", Aliases: []string{"Swiss Confederation"}},
+	{Code: "CI", Code3: "CIV", Name: "Côte d'Ivoire", Aliases: []string{"Republic of Côte d'Ivoire", "Ivory Coast"}},
+	{Code: "CK", Code3: "COK", Name: "Cook Islands"},
+	{Code: "CL", Code3: "CHL", Name: "Chile", Aliases: []string{"Republic of Chile"}},
+	{Code: "CM", Code3: "CMR", Name: "Cameroon", Aliases: []string{"Republic of Cameroon"}},
+	{Code: "CN", Code3: "CHN", Name: "China", Aliases: []string{"People's Republic of China"}},
+	{Code: "CO", Code3: "COL", Name: "Colombia", Aliases: []string{"Republic of Colombia"}},
+	{Code: "CR", Code3: "CRI", Name: "Costa Rica", Aliases: []string{"Republic of Costa Rica"}},
+	{Code: "CU", Code3: "CUB", Name: "Cuba", Aliases: []string{"Republic of Cuba"}},
+	{Code: "CV", Code3: "CPV", Name: "Cabo Verde", Aliases: []string{"Republic of Cabo Verde", "Cape Verde"}},
+	{Code: "CW", Code3: "CUW", Name: "Curaçao"},
+	{Code: "CX", Code3: "CXR", Name: "Christmas Island"},
+	{Code: "CY", Code3: "CYP", Name: "Cyprus", Aliases: []string{"Republic of Cyprus"}},
+	{Code: "CZ", Code3: "CZE", Name: "Czechia", Aliases: []string{"Czech Republic"}},
+	{Code: "DE", Code3: "DEU", Name: "Germany", Aliases: []string{"Federal Republic of Germany"}},
+	{Code: "DJ", Code3: "DJI", Name: "Djibouti", Aliases: []string{"Republic of Djibouti"}},
+	{Code: "DK", Code3: "DNK", Name: "Denmark", Aliases: []string{"Kingdom of Denmark"}},
+	{Code: "DM", Code3: "DMA", Name: "Dominica", Aliases: []string{"Commonwealth of Dominica"}},
+	{Code: "DO", Code3: "DOM", Name: "Dominican Republic"},
+	{Code: "DZ", Code3: "DZA", Name: "Algeria", Aliases: []string{"People's Democratic Republic of Algeria"}},
+	{Code: "EC", Code3: "ECU", Name: "Ecuador", Aliases: []string{"Republic of Ecuador"}},
+	{Code: "EE", Code3: "EST", Name: "Estonia", Aliases: []string{"Republic of Estonia"}},
+	{Code: "EG", Code3: "EGY", Name: "Egypt", Aliases: []string{"Arab Republic of Egypt"}},
+	{Code: "EH", Code3: "ESH", Name: "Western Sahara"},
+	{Code: "ER", Code3: "ERI", Name: "Eritrea", Aliases: []string{"the State of Eritrea"}},
+	{Code: "ES", Code3: "ESP", Name: "Spain", Aliases: []string{"Kingdom of Spain"}},
+	{Code: "ET", Code3: "ETH", Name: "Ethiopia", Aliases: []string{"Federal Democratic Republic of Ethiopia"}},
+	{Code: "FI", Code3: "FIN", Name: "Finland", Aliases: []string{"Republic of Finland"}},
+	{Code: "FJ", Code3: "FJI", Name: "Fiji", Aliases: []string{"Republic of Fiji"}},
+	{Code: "FK", Code3: "FLK", Name: "Falkland Islands (Malvinas)"},
+	{Code: "FM", Code3: "FSM", Name: "Micronesia, Federated States of", Aliases: []string{"Federated States of Micronesia", "Micronesia"}},
+	{Code: "FO", Code3: "FRO", Name: "Faroe Islands"},
+	{Code: "FR", Code3: "FRA", Name: "France", Aliases: []string{"French Republic"}},
+	{Code: "GA", Code3: "GAB", Name: "Gabon", Aliases: []string{"Gabonese Republic"}},
+	{Code: "GB", Code3: "GBR", Name: "United Kingdom", Aliases: []string{"United Kingdom of Great Britain and Northern Ireland", "UK", "Great Britain", "Britain", "England", "Scotland", "Wales", "Northern Ireland"}},
+	{Code: "GD", Code3: "GRD", Name: "Grenada"},
+	{Code: "GE", Code3: "GEO", Name: "Georgia"},
+	{Code: "GF", Code3: "GUF", Name: "French Guiana"},
+	{Code: "GG", Code3: "GGY", Name: "Guernsey"},
+	{Code: "GH", Code3: "GHA", Name: "Ghana", Aliases: []string{"Republic of Ghana"}},
+	{Code: "GI", Code3: "GIB", Name: "Gibraltar"},
+	{Code: "GL", Code3: "GRL", Name: "Greenland"},
+	{Code: "GM", Code3: "GMB", Name: "Gambia", Aliases: []string{"Republic of the Gambia"}},
+	{Code: "GN", Code3: "GIN", Name: "Guinea", Aliases: []string{"Republic of Guinea"}},
+	{Code: "GP", Code3: "GLP", Name: "Guadeloupe"},
+	{Code: "GQ", Code3: "GNQ", Name: "Equatorial Guinea", Aliases: []string{"Republic of Equatorial Guinea"}},
+	{Code: "GR", Code3: "GRC", Name: "Greece", Aliases: []string{"Hellenic Republic"}},
+	{Code: "GS", Code3: "SGS", Name: "South Georgia and the South Sandwich Islands"},
+	{Code: "GT", Code3: "GTM", Name: "Guatemala", Aliases: []string{"Republic of Guatemala"}},
+	{Code: "GU", Code3: "GUM", Name: "Guam"},
+	{Code: "GW", Code3: "GNB", Name: "Guinea-Bissau", Aliases: []string{"Republic of Guinea-Bissau"}},
+	{Code: "GY", Code3: "GUY", Name: "Guyana", Aliases: []string{"Republic of Guyana"}},
+	{Code: "HK", Code3: "HKG", Name: "Hong Kong", Aliases: []string{"Hong Kong Special Administrative Region of China"}},
+	{Code: "HM", Code3: "HMD", Name: "Heard Island and McDonald Islands"},
+	{Code: "HN", Code3: "HND", Name: "Honduras", Aliases: []string{"Republic of Honduras"}},
+	{Code: "HR", Code3: "HRV", Name: "Croatia", Aliases: []string{"Republic of Croatia"}},
+	{Code: "HT", Code3: "HTI", Name: "Haiti", Aliases: []string{"Republic of Haiti"}},
+	{Code: "HU", Code3: "HUN", Name: "Hungary"},
+	{Code: "ID", Code3: "IDN", Name: "Indonesia", Aliases: []string{"Republic of Indonesia"}},
+	{Code: "IE", Code3: "IRL", Name: "Ireland"},
+	{Code: "IL", Code3: "ISR", Name: "Israel", Aliases: []string{"State of Israel"}},
+	{Code: "IM", Code3: "IMN", Name: "Isle of Man"},
+	{Code: "IN", Code3: "IND", Name: "India", Aliases: []string{"Republic of India"}},
+	{Code: "IO", Code3: "IOT", Name: "British Indian Ocean Territory"},
+	{Code: "IQ", Code3: "IRQ", Name: "Iraq", Aliases: []string{"Republic of Iraq"}},
+	{Code: "IR", Code3: "IRN", Name: "Iran", Aliases: []string{"Iran, Islamic Republic of", "Islamic Republic of Iran"}},
+	{Code: "IS", Code3: "ISL", Name: "Iceland", Aliases: []string{"Republic of Iceland"}},
+	{Code: "IT", Code3: "ITA", Name: "Italy", Aliases: []string{"Italian Republic"}},
+	{Code: "JE", Code3: "JEY", Name: "Jersey"},
+	{Code: "JM", Code3: "JAM", Name: "Jamaica"},
+	{Code: "JO", Code3: "JOR", Name: "Jordan", Aliases: []string{"Hashemite Kingdom of Jordan"}},
+	{Code: "JP", Code3: "JPN", Name: "Japan"},
+	{Code: "KE", Code3: "KEN", Name: "Kenya", Aliases: []string{"Republic of Kenya"}},
+	{Code: "KG", Code3: "KGZ", Name: "Kyrgyzstan", Aliases: []string{"Kyrgyz Republic"}},
+	{Code: "KH", Code3: "KHM", Name: "Cambodia", Aliases: []string{"Kingdom of Cambodia"}},
+	{Code: "KI", Code3: "KIR", Name: "Kiribati", Aliases: []string{"Republic of Kiribati"}},
+	{Code: "KM", Code3: "COM", Name: "Comoros", Aliases: []string{"Union of the Comoros"}},
+	{Code: "KN", Code3: "KNA", Name: "Saint Kitts and Nevis"},
+	{Code: "KP", Code3: "PRK", Name: "North Korea", Aliases: []string{"Korea, Democratic People's Republic of", "Democratic People's Republic of Korea"}},
+	{Code: "KR", Code3: "KOR", Name: "South Korea", Aliases: []string{"Korea, Republic of"}},
+	{Code: "KW", Code3: "KWT", Name: "Kuwait", Aliases: []string{"State of Kuwait"}},
+	{Code: "KY", Code3: "CYM", Name: "Cayman Islands"},
+	{Code: "KZ", Code3: "KAZ", Name: "Kazakhstan", Aliases: []string{"Republic of Kazakhstan"}},
+	{Code: "LA", Code3: "LAO", Name: "Laos", Aliases: []string{"Lao People's Democratic Republic"}},
+	{Code: "LB", Code3: "LBN", Name: "Lebanon", Aliases: []string{"Lebanese Republic"}},
+	{Code: "LC", Code3: "LCA", Name: "Saint Lucia"},
+	{Code: "LI", Code3: "LIE", Name: "Liechtenstein", Aliases: []string{"Principality of Liechtenstein"}},
+	{Code: "LK", Code3: "LKA", Name: "Sri Lanka", Aliases: []string{"Democratic Socialist Republic of Sri Lanka"}},
+	{Code: "LR", Code3: "LBR", Name: "Liberia", Aliases: []string{"Republic of Liberia"}},
+	{Code: "LS", Code3: "LSO", Name: "Lesotho", Aliases: []string{"Kingdom of Lesotho"}},
+	{Code: "LT", Code3: "LTU", Name: "Lithuania", Aliases: []string{"Republic of Lithuania"}},
+	{Code: "LU", Code3: "LUX", Name: "Luxembourg", Aliases: []string{"Grand Duchy of Luxembourg"}},
+	{Code: "LV", Code3: "LVA", Name: "Latvia", Aliases: []string{"Republic of Latvia"}},
+	{Code: "LY", Code3: "LBY", Name: "Libya"},
+	{Code: "MA", Code3: "MAR", Name: "Morocco", Aliases: []string{"Kingdom of Morocco"}},
+	{Code: "MC", Code3: "MCO", Name: "Monaco", Aliases: []string{"Principality of Monaco"}},
+	{Code: "MD", Code3: "MDA", Name: "Moldova", Aliases: []string{"Moldova, Republic of", "Republic of Moldova"}},
+	{Code: "ME", Code3: "MNE", Name: "Montenegro"},
+	{Code: "MF", Code3: "MAF", Name: "Saint Martin (French part)"},
+	{Code: "MG", Code3: "MDG", Name: "Madagascar", Aliases: []string{"Republic of Madagascar"}},
+	{Code: "MH", Code3: "MHL", Name: "Marshall Islands", Aliases: []string{"Republic of the Marshall Islands"}},
+	{Code: "MK", Code3: "MKD", Name: "North Macedonia", Aliases: []string{"Republic of North Macedonia", "Macedonia"}},
+	{Code: "ML", Code3: "MLI", Name: "Mali", Aliases: []string{"Republic of Mali"}},
+	{Code: "MM", Code3: "MMR", Name: "Myanmar", Aliases: []string{"Republic of Myanmar", "Burma"}},
+	{Code: "MN", Code3: "MNG", Name: "Mongolia"},
+	{Code: "MO", Code3: "MAC", Name: "Macao", Aliases: []string{"Macao Special Administrative Region of China"}},
+	{Code: "MP", Code3: "MNP", Name: "Northern Mariana Islands", Aliases: []string{"Commonwealth of the Northern Mariana Islands"}},
+	{Code: "MQ", Code3: "MTQ", Name: "Martinique"},
+	{Code: "MR", Code3: "MRT", Name: "Mauritania", Aliases: []string{"Islamic Republic of Mauritania"}},
+	{Code: "MS", Code3: "MSR", Name: "Montserrat"},
+	{Code: "MT", Code3: "MLT", Name: "Malta", Aliases: []string{"Republic of Malta"}},
+	{Code: "MU", Code3: "MUS", Name: "Mauritius", Aliases: []string{"Republic of Mauritius"}},
+	{Code: "MV", Code3: "MDV", Name: "Maldives", Aliases: []string{"Republic of Maldives"}},
+	{Code: "MW", Code3: "MWI", Name: "Malawi", Aliases: []string{"Republic of Malawi"}},
+	{Code: "MX", Code3: "MEX", Name: "Mexico", Aliases: []string{"United Mexican States"}},
+	{Code: "MY", Code3: "MYS", Name: "Malaysia"},
+	{Code: "MZ", Code3: "MOZ", Name: "Mozambique", Aliases: []string{"Republic of Mozambique"}},
+	{Code: "NA", Code3: "NAM", Name: "Namibia", Aliases: []string{"Republic of Namibia"}},
+	{Code: "NC", Code3: "NCL", Name: "New Caledonia"},
+	{Code: "NE", Code3: "NER", Name: "Niger", Aliases: []string{"Republic of the Niger"}},
+	{Code: "NF", Code3: "NFK", Name: "Norfolk Island"},
+	{Code: "NG", Code3: "NGA", Name: "Nigeria", Aliases: []string{"Federal Republic of Nigeria"}},
+	{Code: "NI", Code3: "NIC", Name: "Nicaragua", Aliases: []string{"Republic of Nicaragua"}},
+	{Code: "NL", Code3: "NLD", Name: "Netherlands", Aliases: []string{"Kingdom of the Netherlands", "Holland", "The Netherlands"}},
+	{Code: "NO", Code3: "NOR", Name: "Norway", Aliases: []string{"Kingdom of Norway"}},
+	{Code: "NP", Code3: "NPL", Name: "Nepal", Aliases: []string{"Federal Democratic Republic of Nepal"}},
+	{Code: "NR", Code3: "NRU", Name: "Nauru", Aliases: []string{"Republic of Nauru"}},
+	{Code: "NU", Code3: "NIU", Name: "Niue"},
+	{Code: "NZ", Code3: "NZL", Name: "New Zealand"},
+	{Code: "OM", Code3: "OMN", Name: "Oman", Aliases: []string{"Sultanate of Oman"}},
+	{Code: "PA", Code3: "PAN", Name: "Panama", Aliases: []string{"Republic of Panama"}},
+	{Code: "PE", Code3: "PER", Name: "Peru", Aliases: []string{"Republic of Peru"}},
+	{Code: "PF", Code3: "PYF", Name: "French Polynesia"},
+	{Code: "PG", Code3: "PNG", Name: "Papua New Guinea", Aliases: []string{"Independent State of Papua New Guinea"}},
+	{Code: "PH", Code3: "PHL", Name: "Philippines", Aliases: []string{"Republic of the Philippines"}},
+	{Code: "PK", Code3: "PAK", Name: "Pakistan", Aliases: []string{"Islamic Republic of Pakistan"}},
+	{Code: "PL", Code3: "POL", Name: "Poland", Aliases: []string{"Republic of Poland"}},
+	{Code: "PM", Code3: "SPM", Name: "Saint Pierre and Miquelon"},
+	{Code: "PN", Code3: "PCN", Name: "Pitcairn"},
+	{Code: "PR", Code3: "PRI", Name: "Puerto Rico"},
+	{Code: "PS", Code3: "PSE", Name: "Palestine, State of", Aliases: []string{"the State of Palestine", "Palestine"}},
+	{Code: "PT", Code3: "PRT", Name: "Portugal", Aliases: []string{"Portuguese Republic"}},
+	{Code: "PW", Code3: "PLW", Name: "Palau", Aliases: []string{"Republic of Palau"}},
+	{Code: "PY", Code3: "PRY", Name: "Paraguay", Aliases: []string{"Republic of Paraguay"}},
+	{Code: "QA", Code3: "QAT", Name: "Qatar", Aliases: []string{"State of Qatar"}},
+	{Code: "RE", Code3: "REU", Name: "Réunion"},
+	{Code: "RO", Code3: "ROU", Name: "Romania"},
+	{Code: "RS", Code3: "SRB", Name: "Serbia", Aliases: []string{"Republic of Serbia"}},
+	{Code: "RU", Code3: "RUS", Name: "Russian Federation", Aliases: []string{"Russia"}},
+	{Code: "RW", Code3: "RWA", Name: "Rwanda", Aliases: []string{"Rwandese Republic"}},
+	{Code: "SA", Code3: "SAU", Name: "Saudi Arabia", Aliases: []string{"Kingdom of Saudi Arabia"}},
+	{Code: "SB", Code3: "SLB", Name: "Solomon Islands"},
+	{Code: "SC", Code3: "SYC", Name: "Seychelles", Aliases: []string{"Republic of Seychelles"}},
+	{Code: "SD", Code3: "SDN", Name: "Sudan", Aliases: []string{"Republic of the Sudan"}},
+	{Code: "SE", Code3: "SWE", Name: "Sweden", Aliases: []string{"Kingdom of Sweden"}},
+	{Code: "SG", Code3: "SGP", Name: "Singapore", Aliases: []string{"Republic of Singapore"}},
+	{Code: "SH", Code3: "SHN", Name: "Saint Helena, Ascension and Tristan da Cunha"},
+	{Code: "SI", Code3: "SVN", Name: "Slovenia", Aliases: []string{"Republic of Slovenia"}},
+	{Code: "SJ", Code3: "SJM", Name: "Svalbard and Jan Mayen"},
+	{Code: "SK", Code3: "SVK", Name: "Slovakia", Aliases: []string{"Slovak Republic"}},
+	{Code: "SL", Code3: "SLE", Name: "Sierra Leone", Aliases: []string{"Republic of Sierra Leone"}},
+	{Code: "SM", Code3: "SMR", Name: "San Marino", Aliases: []string{"Republic of San Marino"}},
+	{Code: "SN", Code3: "SEN", Name: "Senegal", Aliases: []string{"Republic of Senegal"}},
+	{Code: "SO", Code3: "SOM", Name: "Somalia", Aliases: []string{"Federal Republic of Somalia"}},
+	{Code: "SR", Code3: "SUR", Name: "Suriname", Aliases: []string{"Republic of Suriname"}},
+	{Code: "SS", Code3: "SSD", Name: "South Sudan", Aliases: []string{"Republic of South Sudan"}},
+	{Code: "ST", Code3: "STP", Name: "Sao Tome and Principe", Aliases: []string{"Democratic Republic of Sao Tome and Principe"}},
+	{Code: "SV", Code3: "SLV", Name: "El Salvador", Aliases: []string{"Republic of El Salvador"}},
+	{Code: "SX", Code3: "SXM", Name: "Sint Maarten (Dutch part)"},
+	{Code: "SY", Code3: "SYR", Name: "Syria", Aliases: []string{"Syrian Arab Republic"}},
+	{Code: "SZ", Code3: "SWZ", Name: "Eswatini", Aliases: []string{"Kingdom of Eswatini", "Swaziland"}},
+	{Code: "TC", Code3: "TCA", Name: "Turks and Caicos Islands"},
+	{Code: "TD", Code3: "TCD", Name: "Chad", Aliases: []string{"Republic of Chad"}},
+	{Code: "TF", Code3: "ATF", Name: "French Southern Territories"},
+	{Code: "TG", Code3: "TGO", Name: "Togo", Aliases: []string{"Togolese Republic"}},
+	{Code: "TH", Code3: "THA", Name: "Thailand", Aliases: []string{"Kingdom of Thailand"}},
+	{Code: "TJ", Code3: "TJK", Name: "Tajikistan", Aliases: []string{"Republic of Tajikistan"}},
+	{Code: "TK", Code3: "TKL", Name: "Tokelau"},
+	{Code: "TL", Code3: "TLS", Name: "Timor-Leste", Aliases: []string{"Democratic Republic of Timor-Leste", "East Timor"}},
+	{Code: "TM", Code3: "TKM", Name: "Turkmenistan"},
+	{Code: "TN", Code3: "TUN", Name: "Tunisia", Aliases: []string{"Republic of Tunisia"}},
+	{Code: "TO", Code3: "TON", Name: "Tonga", Aliases: []string{"Kingdom of Tonga"}},
+	{Code: "TR", Code3: "TUR", Name: "Türkiye", Aliases: []string{"Republic of Türkiye", "Turkey"}},
+	{Code: "TT", Code3: "TTO", Name: "Trinidad and Tobago", Aliases: []string{"Republic of Trinidad and Tobago"}},
+	{Code: "TV", Code3: "TUV", Name: "Tuvalu"},
+	{Code: "TW", Code3: "TWN", Name: "Taiwan", Aliases: []string{"Taiwan, Province of China"}},
+	{Code: "TZ", Code3: "TZA", Name: "Tanzania", Aliases: []string{"Tanzania, United Republic of", "United Republic of Tanzania"}},
+	{Code: "UA", Code3: "UKR", Name: "Ukraine"},
+	{Code: "UG", Code3: "UGA", Name: "Uganda", Aliases: []string{"Republic of Uganda"}},
+	{Code: "UM", Code3: "UMI", Name: "United States Minor Outlying Islands"},
+	{Code: "US", Code3: "USA", Name: "United States", Aliases: []string{"United States of America", "USA", "America"}},
+	{Code: "UY", Code3: "URY", Name: "Uruguay", Aliases: []string{"Eastern Republic of Uruguay"}},
+	{Code: "UZ", Code3: "UZB", Name: "Uzbekistan", Aliases: []string{"Republic of Uzbekistan"}},
+	{Code: "VA", Code3: "VAT", Name: "Holy See (Vatican City State)", Aliases: []string{"Vatican", "Vatican City"}},
+	{Code: "VC", Code3: "VCT", Name: "Saint Vincent and the Grenadines"},
+	{Code: "VE", Code3: "VEN", Name: "Venezuela", Aliases: []string{"Venezuela, Bolivarian Republic of", "Bolivarian Republic of Venezuela"}},
+	{Code: "VG", Code3: "VGB", Name: "Virgin Islands, British", Aliases: []string{"British Virgin Islands"}},
+	{Code: "VI", Code3: "VIR", Name: "Virgin Islands, U.S.", Aliases: []string{"Virgin Islands of the United States"}},
+	{Code: "VN", Code3: "VNM", Name: "Vietnam", Aliases: []string{"Viet Nam", "Socialist Republic of Viet Nam"}},
+	{Code: "VU", Code3: "VUT", Name: "Vanuatu", Aliases: []string{"Republic of Vanuatu"}},
+	{Code: "WF", Code3: "WLF", Name: "Wallis and Futuna"},
+	{Code: "WS", Code3: "WSM", Name: "Samoa", Aliases: []string{"Independent State of Samoa"}},
+	{Code: "YE", Code3: "YEM", Name: "Yemen", Aliases: []string{"Republic of Yemen"}},
+	{Code: "YT", Code3: "MYT", Name: "Mayotte"},
+	{Code: "ZA", Code3: "ZAF", Name: "South Africa", Aliases: []string{"Republic of South Africa"}},
+	{Code: "ZM", Code3: "ZMB", Name: "Zambia", Aliases: []string{"Republic of Zambia"}},
+	{Code: "ZW", Code3: "ZWE", Name: "Zimbabwe", Aliases: []string{"Republic of Zimbabwe"}},
+}
@@ -0,0 +1,223 @@
+package utils
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Country - an ISO 3166-1 country
+type Country struct {
+	Code    string   // alpha-2 code, US
+	Code3   string   // alpha-3 code, USA
+	Name    string   // the name shown to the user, United States
+	Aliases []string // other names it is found by, official and common ones
+}
+
+// foldName - a name reduced to what matters when comparing it, lower case
+// without accents, dots nor apostrophes: "Côte d'Ivoire" is "cote divoire"
+func foldName(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		switch {
+		case unicode.Is(unicode.Mn, r), r == '.', r == '\'', r == '’':
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// FindCountry - the country with this exact name, alias or code
+func FindCountry(s string) (Country, bool) {
+	query := foldName(s)
+	if query == "" {
+		return Country{}, false
+	}
+
+	for _, c := range Countries {
+		if query == strings.ToLower(c.Code) || query == strings.ToLower(c.Code3) || query == foldName(c.Name) {
+			return c, true
+		}
+		for _, alias := range c.Aliases {
+			if query == foldName(alias) {
+				return c, true
+			}
+		}
+	}
+	return Country{}, false
+}
+
+// MatchCountries - the countries s may be, best matches first: names
+// starting with it, having a word starting with it, containing it, then
+// close to it so small typos are forgiven
+func MatchCountries(s string) []Country {
+	if c, ok := FindCountry(s); ok {
+		return []Country{c}
+	}
+
+	query := foldName(s)
+	if query == "" {
+		return nil
+	}
+
+	type match struct {
+		country Country
+		score   int
+	}
+	var matches []match
+	for _, c := range Countries {
+		best := -1
+		for _, name := range append([]string{c.Name}, c.Aliases...) {
+			if score := matchName(query, foldName(name)); score >= 0 && (best < 0 || score < best) {
+				best = score
+			}
+		}
+		if best >= 0 {
+			matches = append(matches, match{c, best})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score < matches[j].score
+	})
+
+	countries := make([]Country, len(matches))
+	for i, m := range matches {
+		countries[i] = m.country
+	}
+	return countries
+}
+
+// matchName - how well query matches name, lower is better, -1 when it
+// doesn't
+func matchName(query, name string) int {
+	switch {
+	case strings.HasPrefix(name, query):
+		return 0
+	case strings.Contains(" "+name, " "+query):
+		return 1
+	case strings.Contains(name, query):
+		return 2
+	}
+
+	// a typo every 3 letters, compared to the start of the name as well for
+	// names longer than what was typed
+	allowed := len(query) / 3
+	if allowed == 0 {
+		return -1
+	}
+	distance := editDistance(query, name)
+	if len(name) > len(query) {
+		if d := editDistance(query, name[:len(query)]); d < distance {
+			distance = d
+		}
+	}
+	if distance > allowed {
+		return -1
+	}
+	return 2 + distance
+}
+
+// editDistance - the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	row := make([]int, len(b)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		prev := row[0]
+		row[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current := row[j]
+			row[j] = prev + cost
+			if row[j-1]+1 < row[j] {
+				row[j] = row[j-1] + 1
+			}
+			if current+1 < row[j] {
+				row[j] = current + 1
+			}
+			prev = current
+		}
+	}
+	return row[len(b)]
+}
+
+// DetectCountry - the country the system is set up for, from its timezone
+// and then its locale. False when neither tells.
+func DetectCountry() (Country, bool) {
+	if code := timezoneCountry(); code != "" {
+		if c, ok := FindCountry(code); ok {
+			return c, true
+		}
+	}
+	if code := localeCountry(); code != "" {
+		if c, ok := FindCountry(code); ok {
+			return c, true
+		}
+	}
+	return Country{}, false
+}
+
+// timezoneCountry - the code of the country of the system timezone, as
+// listed in the zone.tab of the timezone database
+func timezoneCountry() string {
+	zone := strings.TrimPrefix(os.Getenv("TZ"), ":")
+	if zone == "" {
+		target, err := os.Readlink("/etc/localtime")
+		if err != nil {
+			return ""
+		}
+		i := strings.Index(target, "zoneinfo/")
+		if i < 0 {
+			return ""
+		}
+		zone = target[i+len("zoneinfo/"):]
+	}
+	zone = filepath.ToSlash(zone)
+
+	f, err := os.Open("/usr/share/zoneinfo/zone.tab")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	// country code, coordinates, zone name, comments
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) >= 3 && !strings.HasPrefix(fields[0], "#") && fields[2] == zone {
+			return fields[0]
+		}
+	}
+	return ""
+}
+
+// localeCountry - the territory of the locale, US for en_US.UTF-8
+func localeCountry() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		locale := os.Getenv(env)
+		if locale == "" {
+			continue
+		}
+		locale = strings.SplitN(strings.SplitN(locale, ".", 2)[0], "@", 2)[0]
+		parts := strings.FieldsFunc(locale, func(r rune) bool { return r == '_' || r == '-' })
+		if len(parts) == 2 && len(parts[1]) == 2 {
+			return strings.ToUpper(parts[1])
+		}
+		// C, POSIX or only a language, the next variables won't be used either
+		return ""
+	}
+	return ""
+}
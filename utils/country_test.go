@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+func TestFindCountry(t *testing.T) {
+	tests := []struct {
+		query string
+		code  string // none when empty
+	}{
+		{"France", "FR"},
+		{"  france  ", "FR"},
+		{"FR", "FR"},
+		{"fra", "FR"},
+		{"Côte d'Ivoire", "CI"},
+		{"Cote d'Ivoire", "CI"},
+		{"cote divoire", "CI"},
+		{"Côte d’Ivoire", "CI"},
+		{"Ivory Coast", "CI"},
+		{"UK", "GB"},
+		{"GBR", "GB"},
+		{"USA", "US"},
+		{"Holland", "NL"},
+		{"Congo", "CG"},
+		{"Frnace", ""},
+		{"Korea", ""},
+		{"", ""},
+		{"  ", ""},
+	}
+
+	for _, test := range tests {
+		c, ok := FindCountry(test.query)
+		if ok != (test.code != "") || c.Code != test.code {
+			t.Errorf("FindCountry(%q) = %s, %v, want %q", test.query, c.Code, ok, test.code)
+		}
+	}
+}
+
+func TestMatchCountries(t *testing.T) {
+	tests := []struct {
+		query string
+		codes []string // the best matches, in order
+		all   bool     // codes are every match
+	}{
+		{"UK", []string{"GB"}, true},
+		{"Cote d'Ivoire", []string{"CI"}, true},
+		{"ivory", []string{"CI"}, true},
+		{"korea", []string{"KP", "KR"}, true},
+		{"Frnace", []string{"FR"}, true},
+		{"Germny", []string{"DE"}, true},
+		{"Swtzerland", []string{"CH"}, false},
+		{"united", []string{"AE", "GB"}, false},
+		{"zzzz", nil, true},
+		{"", nil, true},
+	}
+
+	for _, test := range tests {
+		var codes []string
+		for _, c := range MatchCountries(test.query) {
+			codes = append(codes, c.Code)
+		}
+		got := codes
+		if !test.all && len(got) > len(test.codes) {
+			got = got[:len(test.codes)]
+		}
+		if !reflect.DeepEqual(got, test.codes) {
+			t.Errorf("MatchCountries(%q) = %v, want %v", test.query, codes, test.codes)
+		}
+	}
+}
+
+func TestLocaleCountry(t *testing.T) {
+	tests := []struct {
+		lcAll, lcMessages, lang string
+		code                    string
+	}{
+		{"", "", "en_US.UTF-8", "US"},
+		{"", "", "fr_FR", "FR"},
+		{"", "", "de_DE@euro", "DE"},
+		{"", "", "pt-BR", "BR"},
+		{"", "", "C.UTF-8", ""},
+		{"", "", "POSIX", ""},
+		{"", "", "en", ""},
+		{"", "", "", ""},
+		{"", "nl_NL.UTF-8", "en_US.UTF-8", "NL"},
+		{"en_GB.UTF-8", "nl_NL.UTF-8", "en_US.UTF-8", "GB"},
+		// C overrides the variables after it
+		{"C", "", "en_US.UTF-8", ""},
+	}
+
+	for _, test := range tests {
+		t.Setenv("LC_ALL", test.lcAll)
+		t.Setenv("LC_MESSAGES", test.lcMessages)
+		t.Setenv("LANG", test.lang)
+		if code := localeCountry(); code != test.code {
+			t.Errorf("localeCountry() with LC_ALL=%q LC_MESSAGES=%q LANG=%q = %q, want %q", test.lcAll, test.lcMessages, test.lang, code, test.code)
+		}
+	}
+}
+
+func TestCountryInputKeys(t *testing.T) {
+	tests := []struct {
+		keys   string
+		answer string
+	}{
+		{"", "France"},
+		{"germ", "Germany"},
+		{"frnace", "France"},
+		{"korea" + string(terminal.KeyArrowDown), "South Korea"},
+		{"korea" + string(terminal.KeyArrowUp), "South Korea"},
+		{"korea" + string(terminal.KeyArrowDown) + string(terminal.KeyArrowDown), "North Korea"},
+		{"uk\t", "United Kingdom"},
+		{"spainx" + string(terminal.KeyBackspace), "Spain"},
+		{"spain" + string(terminal.KeyDeleteWord) + "italy", "Italy"},
+		{"zzzz", "zzzz"},
+	}
+
+	for _, test := range tests {
+		c := &CountryInput{Message: "Country?", Default: "France"}
+		for _, r := range test.keys {
+			c.onKey(r)
+		}
+		if answer := c.answer(); answer != test.answer {
+			t.Errorf("keys %q picked %q, want %q", test.keys, answer, test.answer)
+		}
+	}
+
+	// tab completes the name of the highlighted country
+	c := &CountryInput{}
+	for _, r := range "korea" + string(terminal.KeyArrowDown) + "\t" {
+		c.onKey(r)
+	}
+	if string(c.typed) != "South Korea" {
+		t.Errorf("tab completed %q", string(c.typed))
+	}
+}
+
+func TestLinePrompterCountry(t *testing.T) {
+	// several countries are asked again, the first one that isn't is taken
+	in := strings.NewReader("korea\nfrnace\n\n")
+	p := NewLinePrompter(in, ioutil.Discard)
+
+	var country string
+	err := p.AskOne(&CountryInput{Message: "Country?", Default: "Spain"}, &country, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if country != "France" {
+		t.Errorf("country = %q, want France", country)
+	}
+
+	err = p.AskOne(&CountryInput{Message: "Country?", Default: "Spain"}, &country, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if country != "Spain" {
+		t.Errorf("country = %q, want the default", country)
+	}
+}
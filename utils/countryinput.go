@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"os"
+
+	survey "gopkg.in/AlecAivazis/survey.v1"
+	"gopkg.in/AlecAivazis/survey.v1/core"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// CountryInput - a survey prompt answered with a country, listing the
+// countries matching what is typed while it is typed. The arrows move
+// between them, tab completes the highlighted one and enter picks it. The
+// answer is the name of the country picked, what was typed when nothing
+// matches it, or Default when nothing was typed.
+type CountryInput struct {
+	core.Renderer
+	Message  string
+	Default  string
+	Help     string
+	PageSize int // countries listed, survey.PageSize when 0
+
+	typed       []rune
+	selected    int
+	showingHelp bool
+}
+
+// countryInputData - what the template is rendered with
+type countryInputData struct {
+	CountryInput
+	Typed       string
+	Suggestions []string
+	Selected    int
+	Answer      string
+	ShowAnswer  bool
+	ShowHelp    bool
+}
+
+// CountryInputTemplate - the question, what was typed and the countries it
+// matches below
+var CountryInputTemplate = `
+{{- if .ShowHelp }}{{- color "cyan"}}{{ HelpIcon }} {{ .Help }}{{color "reset"}}{{"\n"}}{{end}}
+{{- color "green+hb"}}{{ QuestionIcon }} {{color "reset"}}
+{{- color "default+hb"}}{{ .Message }} {{color "reset"}}
+{{- if .ShowAnswer}}
+  {{- color "cyan"}}{{.Answer}}{{color "reset"}}{{"\n"}}
+{{- else }}
+  {{- if and .Help (not .ShowHelp)}}{{color "cyan"}}[{{ HelpInputRune }} for help]{{color "reset"}} {{end}}
+  {{- if and .Default (not .Typed)}}{{color "white"}}({{.Default}}) {{color "reset"}}{{end}}
+  {{- .Typed}}{{"\n"}}
+  {{- range $ix, $country := .Suggestions}}
+    {{- if eq $ix $.Selected}}{{color "cyan+b"}}{{ SelectFocusIcon }} {{else}}{{color "default+hb"}}  {{end}}
+    {{- $country}}
+    {{- color "reset"}}{{"\n"}}
+  {{- end}}
+{{- end}}`
+
+// Prompt - survey.Prompt
+func (c *CountryInput) Prompt() (interface{}, error) {
+	c.typed, c.selected, c.showingHelp = nil, 0, false
+	err := c.render()
+	if err != nil {
+		return "", err
+	}
+
+	// what is typed is rendered by the template
+	terminal.CursorHide()
+	defer terminal.CursorShow()
+
+	rr := terminal.NewRuneReader(os.Stdin)
+	rr.SetTermMode()
+	defer rr.RestoreTermMode()
+	for {
+		r, _, err := rr.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		switch r {
+		case terminal.KeyInterrupt:
+			return "", terminal.InterruptErr
+		case '\r', '\n', terminal.KeyEndTransmission:
+			return c.answer(), nil
+		}
+
+		c.onKey(r)
+		err = c.render()
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// onKey - update what is typed and the highlighted country with a key
+func (c *CountryInput) onKey(r rune) {
+	suggestions := c.suggestions()
+	switch {
+	case r == '\t':
+		if len(suggestions) > 0 {
+			c.typed = []rune(suggestions[c.selected].Name)
+			c.selected = 0
+		}
+	case r == terminal.KeyArrowDown && len(suggestions) > 0:
+		c.selected = (c.selected + 1) % len(suggestions)
+	case r == terminal.KeyArrowUp && len(suggestions) > 0:
+		c.selected = (c.selected + len(suggestions) - 1) % len(suggestions)
+	case r == core.HelpInputRune && len(c.typed) == 0 && c.Help != "":
+		c.showingHelp = true
+	case r == terminal.KeyBackspace || r == terminal.KeyDelete:
+		if len(c.typed) > 0 {
+			c.typed = c.typed[:len(c.typed)-1]
+			c.selected = 0
+		}
+	case r == terminal.KeyDeleteWord || r == terminal.KeyDeleteLine:
+		c.typed, c.selected = nil, 0
+	case r >= terminal.KeySpace:
+		c.typed = append(c.typed, r)
+		c.selected = 0
+	}
+}
+
+// suggestions - the best countries matching what is typed
+func (c *CountryInput) suggestions() []Country {
+	size := c.PageSize
+	if size <= 0 {
+		size = survey.PageSize
+	}
+	matches := MatchCountries(string(c.typed))
+	if len(matches) > size {
+		matches = matches[:size]
+	}
+	return matches
+}
+
+// answer - what enter picks
+func (c *CountryInput) answer() string {
+	if len(c.typed) == 0 {
+		return c.Default
+	}
+	if suggestions := c.suggestions(); len(suggestions) > 0 {
+		return suggestions[c.selected].Name
+	}
+	return string(c.typed)
+}
+
+func (c *CountryInput) render() error {
+	var names []string
+	for _, country := range c.suggestions() {
+		names = append(names, country.Name)
+	}
+	return c.Render(CountryInputTemplate, countryInputData{
+		CountryInput: *c,
+		Typed:        string(c.typed),
+		Suggestions:  names,
+		Selected:     c.selected,
+		ShowHelp:     c.showingHelp,
+	})
+}
+
+// Cleanup - survey.Prompt, shows the answer
+func (c *CountryInput) Cleanup(val interface{}) error {
+	return c.Render(CountryInputTemplate, countryInputData{
+		CountryInput: *c,
+		Answer:       val.(string),
+		ShowAnswer:   true,
+	})
+}
@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// HTTP Archive 1.2, just the parts needed to share a trace in a bug report
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string       `json:"method"`
+	URL         string       `json:"url"`
+	HTTPVersion string       `json:"httpVersion"`
+	Headers     []harNameVal `json:"headers"`
+	QueryString []harNameVal `json:"queryString"`
+	Cookies     []harNameVal `json:"cookies"`
+	PostData    *harPostData `json:"postData,omitempty"`
+	HeadersSize int          `json:"headersSize"`
+	BodySize    int          `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int          `json:"status"`
+	StatusText  string       `json:"statusText"`
+	HTTPVersion string       `json:"httpVersion"`
+	Headers     []harNameVal `json:"headers"`
+	Cookies     []harNameVal `json:"cookies"`
+	Content     harContent   `json:"content"`
+	RedirectURL string       `json:"redirectURL"`
+	HeadersSize int          `json:"headersSize"`
+	BodySize    int          `json:"bodySize"`
+}
+
+type harNameVal struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+func harHeaders(h map[string]string) []harNameVal {
+	out := []harNameVal{}
+	for name, value := range h {
+		out = append(out, harNameVal{Name: name, Value: value})
+	}
+	return out
+}
+
+// record - add the exchange to the HAR file, which is rewritten after every
+// request so it survives the CLI exiting on an error
+func (t *tracingTransport) record(req *http.Request, reqBody []byte, res *http.Response, resBody []byte, started time.Time, latency time.Duration) {
+	if t.harPath == "" {
+		return
+	}
+
+	ms := float64(latency) / float64(time.Millisecond)
+	entry := harEntry{
+		StartedDateTime: started.Format(time.RFC3339Nano),
+		Time:            ms,
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         RedactURL(req.URL, t.redactFields),
+			HTTPVersion: req.Proto,
+			Headers:     harHeaders(t.headers(req.Header)),
+			QueryString: []harNameVal{},
+			Cookies:     []harNameVal{},
+			HeadersSize: -1,
+			BodySize:    len(reqBody),
+		},
+		Response: harResponse{
+			Headers:     []harNameVal{},
+			Cookies:     []harNameVal{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Timings: harTimings{Wait: ms},
+	}
+
+	for name, values := range req.URL.Query() {
+		for _, value := range values {
+			entry.Request.QueryString = append(entry.Request.QueryString, harNameVal{Name: name, Value: redactQueryValue(name, value, t.redactFields)})
+		}
+	}
+
+	if len(reqBody) > 0 {
+		entry.Request.PostData = &harPostData{
+			MimeType: req.Header.Get("Content-Type"),
+			Text:     string(RedactBody(reqBody, t.redactFields)),
+		}
+	}
+
+	if res != nil {
+		body := RedactBody(resBody, t.redactFields)
+		entry.Response.Status = res.StatusCode
+		entry.Response.StatusText = http.StatusText(res.StatusCode)
+		entry.Response.HTTPVersion = res.Proto
+		entry.Response.Headers = harHeaders(t.headers(res.Header))
+		entry.Response.BodySize = len(resBody)
+		entry.Response.Content = harContent{Size: len(body), MimeType: res.Header.Get("Content-Type"), Text: string(body)}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.entries = append(t.entries, entry)
+	har := harFile{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "gladius-cli", Version: CLIVersion},
+		Entries: t.entries,
+	}}
+
+	b, err := json.MarshalIndent(har, "", "  ")
+	if err == nil {
+		err = ioutil.WriteFile(t.harPath, b, 0600)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"file": "har.go", "func": "record"}).Warning("Could not write HAR file: ", err)
+	}
+}
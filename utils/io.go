@@ -0,0 +1,230 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	survey "gopkg.in/AlecAivazis/survey.v1"
+	"gopkg.in/AlecAivazis/survey.v1/core"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// Prompter - asks the user questions, same signatures as survey
+type Prompter interface {
+	Ask(qs []*survey.Question, response interface{}) error
+	AskOne(p survey.Prompt, response interface{}, v survey.Validator) error
+}
+
+// SurveyPrompter - asks on the terminal with survey
+type SurveyPrompter struct{}
+
+// Ask - survey.Ask
+func (SurveyPrompter) Ask(qs []*survey.Question, response interface{}) error {
+	return survey.Ask(qs, response)
+}
+
+// AskOne - survey.AskOne
+func (SurveyPrompter) AskOne(p survey.Prompt, response interface{}, v survey.Validator) error {
+	return survey.AskOne(p, response, v)
+}
+
+// LinePrompter - asks on any reader, one answer per line, for when stdin is
+// not the terminal survey needs. Select answers are the option or its number,
+// MultiSelect ones are separated by commas, CountryInput ones must match a
+// single country and an empty line takes the default.
+type LinePrompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// NewLinePrompter - prints the questions to out and reads the answers from in
+func NewLinePrompter(in io.Reader, out io.Writer) *LinePrompter {
+	return &LinePrompter{in: bufio.NewReader(in), out: out}
+}
+
+// Ask - survey.Ask
+func (p *LinePrompter) Ask(qs []*survey.Question, response interface{}) error {
+	for _, q := range qs {
+		answer, err := p.ask(q.Prompt, q.Validate)
+		if err != nil {
+			return err
+		}
+		if q.Transform != nil {
+			if transformed := q.Transform(answer); transformed != nil {
+				answer = transformed
+			}
+		}
+		err = core.WriteAnswer(response, q.Name, answer)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AskOne - survey.AskOne
+func (p *LinePrompter) AskOne(prompt survey.Prompt, response interface{}, v survey.Validator) error {
+	return p.Ask([]*survey.Question{{Prompt: prompt, Validate: v}}, response)
+}
+
+// ask - reads answers until one passes v
+func (p *LinePrompter) ask(prompt survey.Prompt, v survey.Validator) (interface{}, error) {
+	for {
+		answer, err := p.answer(prompt)
+		if err == nil && v != nil {
+			err = v(answer)
+		}
+		if err == nil {
+			return answer, nil
+		}
+		if err == io.EOF {
+			return nil, err
+		}
+		fmt.Fprintln(p.out, "X Sorry, your reply was invalid:", err)
+	}
+}
+
+func (p *LinePrompter) answer(prompt survey.Prompt) (interface{}, error) {
+	switch q := prompt.(type) {
+	case *survey.Input:
+		line, err := p.readLine(q.Message, q.Default)
+		if line == "" {
+			line = q.Default
+		}
+		return line, err
+	case *CountryInput:
+		line, err := p.readLine(q.Message, q.Default)
+		if err != nil || line == "" {
+			return q.Default, err
+		}
+		return pickCountry(line)
+	case *survey.Password:
+		return p.readLine(q.Message, "")
+	case *survey.Confirm:
+		def := "y/N"
+		if q.Default {
+			def = "Y/n"
+		}
+		line, err := p.readLine(q.Message, def)
+		if err != nil {
+			return nil, err
+		}
+		switch strings.ToLower(line) {
+		case "":
+			return q.Default, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		return nil, fmt.Errorf("answer yes or no")
+	case *survey.Select:
+		p.printOptions(q.Options)
+		line, err := p.readLine(q.Message, q.Default)
+		if err != nil {
+			return nil, err
+		}
+		if line == "" && q.Default != "" {
+			return q.Default, nil
+		}
+		return pickOption(q.Options, line)
+	case *survey.MultiSelect:
+		p.printOptions(q.Options)
+		line, err := p.readLine(q.Message, strings.Join(q.Default, ", "))
+		if err != nil {
+			return nil, err
+		}
+		if line == "" {
+			return append([]string{}, q.Default...), nil
+		}
+		var picked []string
+		for _, part := range strings.Split(line, ",") {
+			option, err := pickOption(q.Options, strings.TrimSpace(part))
+			if err != nil {
+				return nil, err
+			}
+			picked = append(picked, option)
+		}
+		return picked, nil
+	}
+	return nil, fmt.Errorf("can't ask a %T question without a terminal", prompt)
+}
+
+// readLine - prints the question and reads the answer without the line
+// break, io.EOF once in is exhausted
+func (p *LinePrompter) readLine(message, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(p.out, "? %s (%s) ", message, def)
+	} else {
+		fmt.Fprintf(p.out, "? %s ", message)
+	}
+	line, err := p.in.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	fmt.Fprintln(p.out)
+	return strings.TrimSpace(line), err
+}
+
+func (p *LinePrompter) printOptions(options []string) {
+	for i, option := range options {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, option)
+	}
+}
+
+// pickOption - the option named or numbered by answer
+func pickOption(options []string, answer string) (string, error) {
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+		return options[n-1], nil
+	}
+	for _, option := range options {
+		if strings.EqualFold(option, answer) {
+			return option, nil
+		}
+	}
+	return "", fmt.Errorf("%q is not one of the options", answer)
+}
+
+// pickCountry - the name of the country answer is. What isn't a country is
+// kept for the validator to reject, and when it matches several the best
+// ones are offered.
+func pickCountry(answer string) (string, error) {
+	matches := MatchCountries(answer)
+	switch {
+	case len(matches) == 0:
+		return answer, nil
+	case len(matches) == 1:
+		return matches[0].Name, nil
+	}
+
+	if len(matches) > survey.PageSize {
+		matches = matches[:survey.PageSize]
+	}
+	names := make([]string, len(matches))
+	for i, c := range matches {
+		names[i] = c.Name
+	}
+	return "", fmt.Errorf("%q could be %s, please type one of them", answer, strings.Join(names, ", "))
+}
+
+// Doer - sends requests to the modules, *http.Client or a fake
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Stdout - where everything meant for the user is printed
+var Stdout io.Writer = terminal.NewAnsiStdout()
+
+// Stderr - where notices and warnings are printed
+var Stderr io.Writer = terminal.NewAnsiStderr()
+
+// Prompt - every question the CLI asks goes through it
+var Prompt Prompter = SurveyPrompter{}
+
+// Now - clock used for latencies and cache ages
+var Now = time.Now
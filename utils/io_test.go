@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	survey "gopkg.in/AlecAivazis/survey.v1"
+)
+
+func TestLinePrompter(t *testing.T) {
+	in := strings.NewReader("\n2\nmaybe\ny\nb, 1\n\nshort\nlong enough\n")
+	p := NewLinePrompter(in, ioutil.Discard)
+
+	answers := struct {
+		Name    string
+		Pick    string
+		Sure    bool
+		Several []string
+		Kept    []string
+	}{}
+	qs := []*survey.Question{
+		{Name: "name", Prompt: &survey.Input{Message: "Name?", Default: "node"}},
+		{Name: "pick", Prompt: &survey.Select{Message: "Pick?", Options: []string{"a", "b"}}},
+		{Name: "sure", Prompt: &survey.Confirm{Message: "Sure?"}},
+		{Name: "several", Prompt: &survey.MultiSelect{Message: "Several?", Options: []string{"a", "b"}}},
+		{Name: "kept", Prompt: &survey.MultiSelect{Message: "Kept?", Options: []string{"a", "b"}, Default: []string{"a"}}},
+	}
+	err := p.Ask(qs, &answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if answers.Name != "node" || answers.Pick != "b" || !answers.Sure ||
+		!reflect.DeepEqual(answers.Several, []string{"b", "a"}) || !reflect.DeepEqual(answers.Kept, []string{"a"}) {
+		t.Errorf("answers = %+v", answers)
+	}
+
+	// invalid answers are asked again
+	var secret string
+	long := func(v interface{}) error {
+		if len(v.(string)) < 6 {
+			return errors.New("too short")
+		}
+		return nil
+	}
+	err = p.AskOne(&survey.Password{Message: "Secret?"}, &secret, long)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret != "long enough" {
+		t.Errorf("secret = %q, want the first valid answer", secret)
+	}
+
+	// nothing left to read
+	err = p.AskOne(&survey.Input{Message: "More?"}, &secret, nil)
+	if err == nil {
+		t.Error("no error once the input is exhausted")
+	}
+}
@@ -0,0 +1,184 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gladiusio/gladius-cli/config"
+	"github.com/mgutz/ansi"
+	log "github.com/sirupsen/logrus"
+)
+
+// lockPollInterval - how often a waiting command checks the lock again
+const lockPollInterval = 250 * time.Millisecond
+
+// errLockHeld - another process holds the lock of the file
+var errLockHeld = errors.New("the lock is held by another process")
+
+// lockOwner - contents of the lock file
+type lockOwner struct {
+	PID      int       `json:"pid"`
+	Command  string    `json:"command"`
+	Acquired time.Time `json:"acquired"`
+}
+
+// LockedError - another command holds the lock
+type LockedError struct {
+	PID     int
+	Command string
+}
+
+func (e *LockedError) Error() string {
+	if e.PID <= 0 {
+		// the owner hasn't written itself in the file yet
+		return "another gladius command is running, try again once it is done or use --wait-lock"
+	}
+	return fmt.Sprintf("another gladius command (pid %d, `%s`) is running, try again once it is done or use --wait-lock", e.PID, e.Command)
+}
+
+// LockPath - the lock file held by commands changing the node
+func LockPath() (string, error) {
+	base, err := config.GetGladiusBase()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "gladius.lock"), nil
+}
+
+// AcquireLock - take the lock in the Gladius base dir for command, so only
+// one command changes the node at a time. With wait it blocks until the lock
+// is free or ctx is cancelled, otherwise a *LockedError is returned. The
+// lock is held by the operating system on the open lock file, so it goes
+// away with a command that died. Call release once done, calling it again
+// does nothing.
+func AcquireLock(ctx context.Context, command string, wait bool) (release func(), err error) {
+	path, err := LockPath()
+	if err != nil {
+		return nil, HandleError(err, "Could not find the Gladius base directory", "utils.AcquireLock")
+	}
+	err = os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		return nil, HandleError(err, "Could not create the Gladius base directory", "utils.AcquireLock")
+	}
+
+	waiting := false
+	for {
+		f, owner, err := tryLock(path, command)
+		if err == nil {
+			log.WithFields(log.Fields{"file": "lock.go", "func": "AcquireLock", "lock": path}).Debug("Lock acquired")
+			var once sync.Once
+			return func() { once.Do(func() { releaseLock(f) }) }, nil
+		}
+		if owner == nil {
+			return nil, HandleError(err, "Could not create the lock file "+path, "utils.AcquireLock")
+		}
+
+		locked := &LockedError{PID: owner.PID, Command: owner.Command}
+		if !wait {
+			return nil, HandleError(locked, locked.Error(), "utils.AcquireLock")
+		}
+		if !waiting {
+			waiting = true
+			log.WithFields(log.Fields{"file": "lock.go", "func": "AcquireLock", "pid": owner.PID, "command": owner.Command}).Info("Waiting for the lock")
+			notice := "Waiting for another gladius command to finish..."
+			if owner.PID > 0 {
+				notice = fmt.Sprintf("Waiting for another gladius command (pid %d, `%s`) to finish...", owner.PID, owner.Command)
+			}
+			fmt.Fprintln(Stderr, ansi.Color(notice, "255+hb"))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, HandleError(ctx.Err(), "Cancelled while waiting for the lock", "utils.AcquireLock")
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// tryLock - lock the lock file and write the owner in it. When another
+// process holds the lock its owner is returned with errLockHeld. The file is
+// never removed, so every command locks the same file.
+func tryLock(path, command string) (*os.File, *lockOwner, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = lockFile(f)
+	if err != nil {
+		f.Close()
+		if err == errLockHeld {
+			return nil, readLock(path), err
+		}
+		return nil, nil, err
+	}
+
+	// an owner left in the file is a command that died holding the lock
+	if previous := readOwner(f); previous.PID > 0 {
+		log.WithFields(log.Fields{"file": "lock.go", "func": "tryLock", "pid": previous.PID, "command": previous.Command}).Warning("Taking over the lock of a command that is gone")
+	}
+
+	err = writeOwner(f, &lockOwner{PID: os.Getpid(), Command: command, Acquired: time.Now()})
+	if err != nil {
+		unlockFile(f)
+		f.Close()
+		return nil, nil, err
+	}
+	return f, nil, nil
+}
+
+// readLock - the owner written in the lock file, empty when it can't be read
+func readLock(path string) *lockOwner {
+	f, err := os.Open(path)
+	if err != nil {
+		return &lockOwner{}
+	}
+	defer f.Close()
+	return readOwner(f)
+}
+
+func readOwner(f *os.File) *lockOwner {
+	owner := &lockOwner{}
+	_, err := f.Seek(0, io.SeekStart)
+	if err != nil {
+		return owner
+	}
+	b, err := ioutil.ReadAll(f)
+	if err != nil || json.Unmarshal(b, owner) != nil {
+		return &lockOwner{}
+	}
+	return owner
+}
+
+// writeOwner - replace the content of the lock file, nil empties it
+func writeOwner(f *os.File, owner *lockOwner) error {
+	err := f.Truncate(0)
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil || owner == nil {
+		return err
+	}
+	return json.NewEncoder(f).Encode(owner)
+}
+
+// releaseLock - empty the lock file, so the next command doesn't think this
+// one died, and unlock it
+func releaseLock(f *os.File) {
+	err := writeOwner(f, nil)
+	if err == nil {
+		err = unlockFile(f)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"file": "lock.go", "func": "releaseLock"}).Warning("Could not release the lock file: ", err)
+	}
+	f.Close()
+}
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gladiusio/gladius-cli/config"
+)
+
+// lockIn - a fresh base dir holding the lock file, its path is returned
+func lockIn(t *testing.T) string {
+	dir := t.TempDir()
+	config.SetBaseDir(dir)
+	t.Cleanup(func() { config.SetBaseDir("") })
+
+	path, err := LockPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Dir(path) != dir {
+		t.Fatalf("lock file %s isn't in the base dir %s", path, dir)
+	}
+	return path
+}
+
+func owner(t *testing.T, path string) lockOwner {
+	t.Helper()
+	var o lockOwner
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) > 0 {
+		err = json.Unmarshal(b, &o)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return o
+}
+
+// a lock file left by a command that died holds its owner but no lock
+func TestLockStale(t *testing.T) {
+	path := lockIn(t)
+	err := ioutil.WriteFile(path, []byte(`{"pid": 999999, "command": "start"}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release, err := AcquireLock(context.Background(), "stop", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o := owner(t, path); o.PID != os.Getpid() || o.Command != "stop" {
+		t.Errorf("owner after taking over = %+v", o)
+	}
+
+	release()
+	release()
+	if o := owner(t, path); o.PID != 0 {
+		t.Errorf("owner after releasing = %+v, want none", o)
+	}
+}
+
+// a lock file that isn't JSON is taken over the same way
+func TestLockCorrupt(t *testing.T) {
+	path := lockIn(t)
+	err := ioutil.WriteFile(path, []byte("{not json"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release, err := AcquireLock(context.Background(), "apply", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	if o := owner(t, path); o.PID != os.Getpid() {
+		t.Errorf("owner after taking over = %+v", o)
+	}
+}
+
+// a lock held by a live command is never taken over, whatever its file says
+func TestLockLive(t *testing.T) {
+	path := lockIn(t)
+
+	release, err := AcquireLock(context.Background(), "start", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = AcquireLock(context.Background(), "stop", false)
+	locked, ok := err.(*ErrorResponse)
+	if !ok || !strings.Contains(locked.LogError, "`start`") {
+		t.Fatalf("second lock = %v, want a locked error", err)
+	}
+
+	// the file claiming a dead owner doesn't free a held lock
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"pid": 999999, "command": "start"}`)
+	f.Close()
+	_, err = AcquireLock(context.Background(), "stop", false)
+	if err == nil {
+		t.Fatal("took over a held lock")
+	}
+
+	// a waiting command gets it once released
+	go func() {
+		time.Sleep(2 * lockPollInterval)
+		release()
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	second, err := AcquireLock(ctx, "stop", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second()
+}
@@ -1,54 +1,202 @@
 package utils
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/gladiusio/gladius-utils/config"
+	"github.com/gladiusio/gladius-cli/config"
 	log "github.com/sirupsen/logrus"
 )
 
-// LogLevel - What kind of logs to show (1 = Debug and above, 2 = Info and above, 3 = Warnings and above, 4 = Fatal)
-var LogLevel int
+// LogLevel - What kind of logs to show (debug, info, warn or error).
+// The old numeric levels 1 (debug) to 4 (fatal) are still accepted.
+var LogLevel string
 
-// LogFile - Where the logs are stored
-var LogFile *os.File
+// LogFormat - Format of the log entries (text or json)
+var LogFormat string
 
-// SetLogLevel - Sets the appropriate logging level.
+// LogPath - Where to write the logs, "stderr" for standard error. Defaults
+// to the rotated log file in DirLogs.
+var LogPath string
+
+// LogFile - Where the logs are stored, nil when not logging to the rotated file
+var LogFile *RotatingFile
+
+// RequestID - Identifies every log entry of this invocation
+var RequestID string
+
+// secretFlags - flags whose values never make it into the logs
+var secretFlags = []string{"passphrase", "password", "private-key", "key", "secret", "token"}
+
+// ParseLogLevel - Parses a level name, or one of the old numeric levels.
 // 1 = Debug < , 2 = Info <, 3 = Warning <, 4 = Fatal.
-func SetLogLevel(level int) {
-	switch level {
-	case 1:
-		log.SetLevel(log.DebugLevel)
-	case 2:
-		log.SetLevel(log.InfoLevel)
-	case 3:
-		log.SetLevel(log.WarnLevel)
+func ParseLogLevel(level string) (log.Level, error) {
+	switch strings.ToLower(level) {
+	case "1", "debug":
+		return log.DebugLevel, nil
+	case "2", "info":
+		return log.InfoLevel, nil
+	case "3", "warn", "warning":
+		return log.WarnLevel, nil
+	case "error":
+		return log.ErrorLevel, nil
+	case "4", "fatal":
+		return log.FatalLevel, nil
+	}
+
+	return log.InfoLevel, fmt.Errorf("unknown log level %q, use debug, info, warn or error", level)
+}
+
+// SetupLogger - Configures level, format and output of the logs from the
+// Log* settings, tags every entry with the command and the request ID, and
+// writes a header for this invocation
+func SetupLogger(command string) error {
+	level, err := ParseLogLevel(LogLevel)
+	if err != nil {
+		return err
+	}
+
+	var formatter log.Formatter
+	switch strings.ToLower(LogFormat) {
+	case "", "text":
+		formatter = &log.TextFormatter{}
+	case "json":
+		formatter = &log.JSONFormatter{}
 	default:
-		log.SetLevel(log.FatalLevel)
+		return fmt.Errorf("unknown log format %q, use text or json", LogFormat)
 	}
+
+	out, err := logOutput()
+	if err != nil {
+		return err
+	}
+
+	if RequestID == "" {
+		RequestID = newRequestID()
+	}
+
+	log.SetLevel(level)
+	log.SetFormatter(formatter)
+	log.SetOutput(out)
+	// replaces the hook of a previous command tree run in this process, so
+	// entries aren't tagged twice
+	log.StandardLogger().Hooks = make(log.LevelHooks)
+	log.AddHook(&contextHook{fields: log.Fields{"command": command, "request_id": RequestID}})
+
+	log.WithFields(log.Fields{
+		"version": CLIVersion,
+		"pid":     os.Getpid(),
+		"args":    strings.Join(RedactArgs(os.Args[1:]), " "),
+	}).Info("gladius invoked")
+
+	return nil
 }
 
-// SetupLogger - Clears the previous file, and creates log file ready for writing
-func SetupLogger() error {
-	logPath := config.GetString("DirLogs")
+// logOutput - opens the writer the logs go to
+func logOutput() (io.Writer, error) {
+	if strings.ToLower(LogPath) == "stderr" {
+		return Stderr, nil
+	}
+
+	cfg, err := config.Get()
+	if err != nil {
+		return nil, err
+	}
 
-	// clear previous log file
-	os.Remove(logPath + "/log")
+	path := LogPath
+	if path == "" {
+		path = filepath.Join(cfg.DirLogs, "log")
+	}
 
-	os.MkdirAll(logPath, os.ModePerm)
+	LogFile = &RotatingFile{
+		Path:        path,
+		MaxSize:     int64(cfg.Log.MaxSizeMB) * 1024 * 1024,
+		RotateAfter: time.Duration(cfg.Log.RotateDays) * 24 * time.Hour,
+		MaxAge:      time.Duration(cfg.Log.MaxAgeDays) * 24 * time.Hour,
+		MaxBackups:  cfg.Log.MaxBackups,
+		Compress:    cfg.Log.Compress,
+	}
 
-	err := os.Chdir(logPath)
+	err = LogFile.Open()
 	if err != nil {
-		PrintError(err)
+		LogFile = nil
+		return nil, HandleError(err, "Could not open the log file "+path, "utils.SetupLogger")
 	}
 
-	LogFile, err := os.OpenFile("log", os.O_CREATE|os.O_WRONLY, 0666)
+	return LogFile, nil
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	_, err := rand.Read(b)
 	if err != nil {
-		log.Warning("Failed to log to file, using default stderr")
-		return err
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
 	}
+	return hex.EncodeToString(b)
+}
 
-	log.SetOutput(LogFile)
+// contextHook - adds the same fields to every entry
+type contextHook struct {
+	fields log.Fields
+}
+
+func (h *contextHook) Levels() []log.Level {
+	return log.AllLevels
+}
 
+func (h *contextHook) Fire(entry *log.Entry) error {
+	for key, value := range h.fields {
+		if _, ok := entry.Data[key]; !ok {
+			entry.Data[key] = value
+		}
+	}
 	return nil
 }
+
+// RedactArgs - copy of the command line arguments with the values of secret
+// flags replaced
+func RedactArgs(args []string) []string {
+	redacted := make([]string, len(args))
+	copy(redacted, args)
+
+	for i := 0; i < len(redacted); i++ {
+		name := strings.TrimLeft(redacted[i], "-")
+		if name == redacted[i] {
+			continue // not a flag
+		}
+
+		value := ""
+		if eq := strings.Index(name, "="); eq >= 0 {
+			name, value = name[:eq], name[eq+1:]
+		}
+		if !isSecretFlag(name) {
+			continue
+		}
+
+		if value != "" {
+			redacted[i] = redacted[i][:strings.Index(redacted[i], "=")+1] + "[REDACTED]"
+		} else if i+1 < len(redacted) {
+			i++
+			redacted[i] = "[REDACTED]"
+		}
+	}
+
+	return redacted
+}
+
+func isSecretFlag(name string) bool {
+	name = strings.ToLower(name)
+	for _, secret := range secretFlags {
+		if name == secret || strings.HasSuffix(name, "-"+secret) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func TestSetupLoggerTwice(t *testing.T) {
+	LogLevel, LogFormat, LogPath = "info", "json", "stderr"
+	defer func() { LogLevel, LogFormat, LogPath = "", "", "" }()
+
+	for _, command := range []string{"gladius status", "gladius version"} {
+		err := SetupLogger(command)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for level, hooks := range log.StandardLogger().Hooks {
+		if len(hooks) != 1 {
+			t.Errorf("%d hooks for level %s, want 1", len(hooks), level)
+		}
+	}
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	log.Info("hello")
+
+	var entry map[string]interface{}
+	err := json.Unmarshal(buf.Bytes(), &entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry["command"] != "gladius version" {
+		t.Errorf("command = %v, want the one of the last setup", entry["command"])
+	}
+}
@@ -0,0 +1,208 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RetryPolicy - how idempotent requests are retried and when a host is
+// skipped altogether
+type RetryPolicy struct {
+	Attempts         int           // total attempts for GET/HEAD requests, 1 disables retrying
+	BaseDelay        time.Duration // delay before the first retry, doubled for every retry
+	MaxDelay         time.Duration // upper bound of the delay between retries
+	BreakerThreshold int           // consecutive failures that open the circuit of a host, 0 disables it
+	BreakerCooldown  time.Duration // how long an open circuit skips a host
+}
+
+// DefaultRetry - policy of the requests whose context carries no client
+var DefaultRetry = RetryPolicy{
+	Attempts:         3,
+	BaseDelay:        200 * time.Millisecond,
+	MaxDelay:         2 * time.Second,
+	BreakerThreshold: 3,
+	BreakerCooldown:  30 * time.Second,
+}
+
+// CircuitOpenError - returned without sending anything when a host failed
+// too often recently
+type CircuitOpenError struct {
+	Host  string
+	Until time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s is unavailable, skipping it until %s", e.Host, e.Until.Format("15:04:05"))
+}
+
+type retryTransport struct {
+	next         http.RoundTripper
+	breakers     *breakerSet
+	redactFields []string
+
+	mu     sync.Mutex
+	policy RetryPolicy
+}
+
+// SetRetryPolicy - change the retry policy of the client ctx carries, for
+// the requests sent from now on. False when the client wasn't made by
+// NewClient.
+func SetRetryPolicy(ctx context.Context, policy RetryPolicy) bool {
+	client, ok := clientFrom(ctx).(*http.Client)
+	if !ok {
+		return false
+	}
+	t, ok := client.Transport.(*retryTransport)
+	if !ok {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.policy = policy
+	return true
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	policy := t.policy
+	t.mu.Unlock()
+
+	attempts := 1
+	if req.Method == "GET" || req.Method == "HEAD" {
+		attempts = policy.Attempts
+	}
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	host := req.URL.Host
+	for attempt := 1; ; attempt++ {
+		if until, open := t.breakers.open(host, policy); open {
+			return nil, &CircuitOpenError{Host: host, Until: until}
+		}
+
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		res, err := t.next.RoundTrip(req)
+		retryable := isRetryable(res, err)
+		t.breakers.record(host, policy, retryable)
+
+		if !retryable || attempt >= attempts {
+			return res, err
+		}
+
+		if res != nil {
+			res.Body.Close()
+		}
+
+		delay := backoff(policy, attempt)
+		log.WithFields(log.Fields{"file": "retry.go", "func": "RoundTrip", "url": RedactURL(req.URL, t.redactFields), "attempt": attempt}).Debug("Request failed, retrying in ", delay)
+
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// isRetryable - connection failures and 5xx responses are worth retrying,
+// 4xx responses and other errors are not
+func isRetryable(res *http.Response, err error) bool {
+	if err != nil {
+		opErr, ok := err.(*net.OpError)
+		return ok && opErr.Op == "dial"
+	}
+
+	return res.StatusCode >= 500
+}
+
+// backoff - exponential delay with full jitter
+func backoff(policy RetryPolicy, attempt int) time.Duration {
+	delay := policy.BaseDelay << uint(attempt-1)
+	if delay > policy.MaxDelay || delay <= 0 {
+		delay = policy.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(delay)) + 1)
+}
+
+type breaker struct {
+	failures  int
+	openUntil time.Time
+}
+
+type breakerSet struct {
+	mu    sync.Mutex
+	hosts map[string]*breaker
+}
+
+// newBreakerSet - circuit of every host contacted through a client
+func newBreakerSet() *breakerSet {
+	return &breakerSet{hosts: make(map[string]*breaker)}
+}
+
+func (s *breakerSet) open(host string, policy RetryPolicy) (time.Time, bool) {
+	if policy.BreakerThreshold < 1 {
+		return time.Time{}, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.hosts[host]
+	if !ok || b.openUntil.IsZero() {
+		return time.Time{}, false
+	}
+	if Now().Before(b.openUntil) {
+		return b.openUntil, true
+	}
+
+	// cooldown is over, let one request through to probe the host
+	b.openUntil = time.Time{}
+	b.failures = policy.BreakerThreshold - 1
+	return time.Time{}, false
+}
+
+func (s *breakerSet) record(host string, policy RetryPolicy, failed bool) {
+	if policy.BreakerThreshold < 1 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.hosts[host]
+	if !ok {
+		b = &breaker{}
+		s.hosts[host] = b
+	}
+
+	if !failed {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= policy.BreakerThreshold {
+		b.openUntil = Now().Add(policy.BreakerCooldown)
+		log.WithFields(log.Fields{"file": "retry.go", "func": "record", "host": host}).Warning("Host keeps failing, skipping it for ", policy.BreakerCooldown)
+	}
+}
@@ -0,0 +1,228 @@
+package utils
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+var errRefused = &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+
+func status(code int) *http.Response {
+	return &http.Response{StatusCode: code, Body: ioutil.NopCloser(strings.NewReader(""))}
+}
+
+// countingModule - answers every request with res and err, counting them
+func countingModule(calls *int, res func() *http.Response, err error) roundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		*calls++
+		if err != nil {
+			return nil, err
+		}
+		return res(), nil
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		err       error
+		retryable bool
+	}{
+		{"refused", 0, errRefused, true},
+		{"dial timeout", 0, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("i/o timeout")}, true},
+		{"reset while reading", 0, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, false},
+		{"other error", 0, errors.New("net/http: request canceled"), false},
+		{"ok", 200, nil, false},
+		{"bad request", 400, nil, false},
+		{"not found", 404, nil, false},
+		{"conflict", 409, nil, false},
+		{"too many requests", 429, nil, false},
+		{"internal error", 500, nil, true},
+		{"bad gateway", 502, nil, true},
+		{"unavailable", 503, nil, true},
+	}
+
+	for _, test := range tests {
+		var res *http.Response
+		if test.err == nil {
+			res = status(test.status)
+		}
+		if got := isRetryable(res, test.err); got != test.retryable {
+			t.Errorf("%s: isRetryable = %v, want %v", test.name, got, test.retryable)
+		}
+	}
+}
+
+func TestRetryMethods(t *testing.T) {
+	policy := RetryPolicy{Attempts: 3, BaseDelay: time.Microsecond, MaxDelay: time.Microsecond}
+	tests := []struct {
+		method   string
+		attempts int
+	}{
+		{"GET", 3},
+		{"HEAD", 3},
+		{"POST", 1},
+		{"PUT", 1},
+		{"PATCH", 1},
+		{"DELETE", 1},
+	}
+
+	for _, test := range tests {
+		for _, failure := range []error{errRefused, nil} {
+			calls := 0
+			module := countingModule(&calls, func() *http.Response { return status(503) }, failure)
+			rt := &retryTransport{next: module, policy: policy, breakers: newBreakerSet()}
+
+			req, _ := http.NewRequest(test.method, "http://localhost:3001/api/keystore", strings.NewReader(`{"passphrase":"x"}`))
+			res, err := rt.RoundTrip(req)
+			if calls != test.attempts {
+				t.Errorf("%s failing with %v sent %d times, want %d", test.method, failure, calls, test.attempts)
+			}
+			if failure != nil && err != failure {
+				t.Errorf("%s: error %v, want the last failure", test.method, err)
+			}
+			if failure == nil && (err != nil || res.StatusCode != 503) {
+				t.Errorf("%s: %v, %v, want the last 503", test.method, res, err)
+			}
+		}
+	}
+}
+
+func TestRetryUntilAnswered(t *testing.T) {
+	codes := []int{503, 502, 200}
+	var bodies []string
+	module := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		b, _ := ioutil.ReadAll(req.Body)
+		bodies = append(bodies, string(b))
+		code := codes[0]
+		codes = codes[1:]
+		return status(code), nil
+	})
+	rt := &retryTransport{next: module, policy: RetryPolicy{Attempts: 5, BaseDelay: time.Microsecond, MaxDelay: time.Microsecond}, breakers: newBreakerSet()}
+
+	req, _ := http.NewRequest("GET", "http://localhost:3001/api/status", strings.NewReader("query"))
+	res, err := rt.RoundTrip(req)
+	if err != nil || res.StatusCode != 200 {
+		t.Fatalf("%v, %v, want the 200", res, err)
+	}
+	if len(bodies) != 3 {
+		t.Fatalf("sent %d times, want 3", len(bodies))
+	}
+	for i, body := range bodies {
+		if body != "query" {
+			t.Errorf("attempt %d sent %q, want the whole body again", i+1, body)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt := 1; attempt <= 70; attempt++ {
+		limit := policy.MaxDelay
+		if attempt <= 4 {
+			limit = policy.BaseDelay << uint(attempt-1)
+		}
+		for i := 0; i < 100; i++ {
+			delay := backoff(policy, attempt)
+			if delay <= 0 || delay > limit {
+				t.Fatalf("attempt %d waits %s, want within (0, %s]", attempt, delay, limit)
+			}
+		}
+	}
+
+	if delay := backoff(RetryPolicy{}, 3); delay != 0 {
+		t.Errorf("waits %s without delays", delay)
+	}
+	// a base delay above the maximum is capped too
+	if delay := backoff(RetryPolicy{BaseDelay: time.Minute, MaxDelay: time.Second}, 1); delay > time.Second {
+		t.Errorf("waits %s, want at most a second", delay)
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	clock := useFakeClock(t)
+	policy := RetryPolicy{Attempts: 1, BreakerThreshold: 2, BreakerCooldown: 30 * time.Second}
+
+	calls := 0
+	var failure error = errRefused
+	module := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		if failure != nil {
+			return nil, failure
+		}
+		return status(200), nil
+	})
+	rt := &retryTransport{next: module, policy: policy, breakers: newBreakerSet()}
+	send := func(url string) error {
+		req, _ := http.NewRequest("GET", url, nil)
+		_, err := rt.RoundTrip(req)
+		return err
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := send("http://localhost:3001/api/status"); err != errRefused {
+			t.Fatalf("request %d: %v, want the failure", i+1, err)
+		}
+	}
+
+	// open: nothing is sent to the host until the cooldown is over
+	err := send("http://localhost:3001/api/status")
+	open, ok := err.(*CircuitOpenError)
+	if !ok || open.Host != "localhost:3001" || !open.Until.Equal(clock.now.Add(30*time.Second)) {
+		t.Fatalf("error %v, want the circuit open for 30s", err)
+	}
+	if calls != 2 {
+		t.Errorf("%d requests sent, want 2", calls)
+	}
+
+	// other hosts are still contacted
+	failure = nil
+	if err := send("http://localhost:7791/api/status"); err != nil {
+		t.Errorf("another host: %v", err)
+	}
+
+	// a single probe failing opens the circuit again
+	failure = errRefused
+	clock.advance(30*time.Second + time.Millisecond)
+	if err := send("http://localhost:3001/api/status"); err != errRefused {
+		t.Fatalf("probe: %v, want it sent", err)
+	}
+	if _, ok := send("http://localhost:3001/api/status").(*CircuitOpenError); !ok {
+		t.Error("the circuit is closed after the probe failed")
+	}
+
+	// a probe succeeding closes it
+	failure = nil
+	clock.advance(31 * time.Second)
+	if err := send("http://localhost:3001/api/status"); err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	failure = errRefused
+	if err := send("http://localhost:3001/api/status"); err != errRefused {
+		t.Errorf("after closing: %v, want the request sent", err)
+	}
+	if err := send("http://localhost:3001/api/status"); err != errRefused {
+		t.Errorf("a second failure after closing: %v, want the request sent", err)
+	}
+	if _, ok := send("http://localhost:3001/api/status").(*CircuitOpenError); !ok {
+		t.Error("the circuit didn't open after failing again")
+	}
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	calls := 0
+	rt := &retryTransport{next: countingModule(&calls, nil, errRefused), policy: RetryPolicy{Attempts: 1}, breakers: newBreakerSet()}
+	for i := 0; i < 10; i++ {
+		req, _ := http.NewRequest("GET", "http://localhost:3001/api/status", nil)
+		if _, err := rt.RoundTrip(req); err != errRefused {
+			t.Fatalf("request %d: %v, want it sent", i+1, err)
+		}
+	}
+}
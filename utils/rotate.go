@@ -0,0 +1,246 @@
+package utils
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat - suffix of rotated log files, sorts chronologically
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotatingFile - log file that is rotated once it grows past MaxSize or
+// was started more than RotateAfter ago. Rotated files older than MaxAge, or
+// beyond the newest MaxBackups, are removed. A nil *RotatingFile is safe to
+// Close.
+type RotatingFile struct {
+	Path        string
+	MaxSize     int64         // bytes, 0 disables size based rotation
+	RotateAfter time.Duration // 0 disables age based rotation
+	MaxAge      time.Duration // 0 keeps backups regardless of age
+	MaxBackups  int           // 0 keeps every backup
+	Compress    bool          // gzip rotated files
+
+	mu      sync.Mutex
+	file    *os.File
+	size    int64
+	started time.Time // when the current file was started
+}
+
+// Open - open (or create) the log file for appending, rotating it first if
+// it is already too large or too old
+func (r *RotatingFile) Open() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := os.MkdirAll(filepath.Dir(r.Path), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	r.started = time.Time{}
+	if info, err := os.Stat(r.Path); err == nil {
+		r.started = r.readStarted()
+		if r.tooLarge(info.Size(), 0) || r.tooOld() {
+			err = r.rotate()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return r.openFile()
+}
+
+func (r *RotatingFile) openFile() error {
+	f, err := os.OpenFile(r.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	r.file = f
+	r.size = info.Size()
+	if r.size == 0 || r.started.IsZero() {
+		r.writeStarted()
+	}
+	return nil
+}
+
+// startedPath - holds when the current file was started, as neither its
+// modification nor its change time tell
+func (r *RotatingFile) startedPath() string {
+	return r.Path + ".started"
+}
+
+// readStarted - when the current file was started, zero when unknown
+func (r *RotatingFile) readStarted() time.Time {
+	b, err := ioutil.ReadFile(r.startedPath())
+	if err != nil {
+		return time.Time{}
+	}
+	started, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(b)))
+	if err != nil {
+		return time.Time{}
+	}
+	return started
+}
+
+// writeStarted - the current file starts now. A file from before the start
+// was recorded is counted from now on.
+func (r *RotatingFile) writeStarted() {
+	r.started = Now()
+	ioutil.WriteFile(r.startedPath(), []byte(r.started.Format(time.RFC3339Nano)+"\n"), 0644)
+}
+
+// tooLarge - writing n more bytes to a file of size bytes goes past MaxSize.
+// An empty file takes any write.
+func (r *RotatingFile) tooLarge(size, n int64) bool {
+	if r.MaxSize <= 0 || size == 0 {
+		return false
+	}
+	if n == 0 {
+		return size >= r.MaxSize
+	}
+	return size+n > r.MaxSize
+}
+
+// tooOld - the current file was started more than RotateAfter ago
+func (r *RotatingFile) tooOld() bool {
+	return r.RotateAfter > 0 && !r.started.IsZero() && Now().Sub(r.started) >= r.RotateAfter
+}
+
+// Write - append to the log, rotating when it would grow past MaxSize or is
+// too old
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if r.tooLarge(r.size, int64(len(p))) || (r.size > 0 && r.tooOld()) {
+		r.file.Close()
+		r.file = nil
+		err := r.rotate()
+		if err != nil {
+			return 0, err
+		}
+		err = r.openFile()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close - close the log file
+func (r *RotatingFile) Close() error {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// rotate - move the current file aside and apply the retention policy
+func (r *RotatingFile) rotate() error {
+	backup := r.Path + "." + Now().Format(backupTimeFormat)
+	err := os.Rename(r.Path, backup)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err == nil && r.Compress {
+		err = compressFile(backup)
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.prune()
+}
+
+// prune - remove backups that are too old or too many
+func (r *RotatingFile) prune() error {
+	backups, err := filepath.Glob(r.Path + ".*")
+	if err != nil {
+		return err
+	}
+
+	// newest first
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+
+	prefix := filepath.Base(r.Path) + "."
+	kept := 0
+	for _, backup := range backups {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(backup), prefix), ".gz")
+		created, err := time.ParseInLocation(backupTimeFormat, stamp, time.Local)
+		if err != nil {
+			continue // not one of ours
+		}
+
+		tooMany := r.MaxBackups > 0 && kept >= r.MaxBackups
+		tooOld := r.MaxAge > 0 && Now().Sub(created) > r.MaxAge
+		if tooMany || tooOld {
+			os.Remove(backup)
+			continue
+		}
+		kept++
+	}
+
+	return nil
+}
+
+func compressFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		in.Close()
+		return err
+	}
+
+	gz := gzip.NewWriter(out)
+	_, err = io.Copy(gz, in)
+	in.Close()
+	if err == nil {
+		err = gz.Close()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path + ".gz")
+		return fmt.Errorf("compressing %s: %s", path, err)
+	}
+
+	return os.Remove(path)
+}
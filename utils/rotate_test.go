@@ -0,0 +1,248 @@
+package utils
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock - Now returns the time of the clock until the test ends
+type fakeClock struct{ now time.Time }
+
+func useFakeClock(t *testing.T) *fakeClock {
+	clock := &fakeClock{now: time.Date(2018, 8, 20, 18, 0, 0, 0, time.Local)}
+	previous := Now
+	Now = func() time.Time { return clock.now }
+	t.Cleanup(func() { Now = previous })
+	return clock
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// backups - the rotated files of path, oldest first
+func backups(t *testing.T, path string) []string {
+	t.Helper()
+	files, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rotated []string
+	for _, file := range files {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), filepath.Base(path)+"."), ".gz")
+		if _, err := time.Parse(backupTimeFormat, stamp); err == nil {
+			rotated = append(rotated, file)
+		}
+	}
+	sort.Strings(rotated)
+	return rotated
+}
+
+func read(t *testing.T, path string) string {
+	t.Helper()
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func write(t *testing.T, r *RotatingFile, s string) {
+	t.Helper()
+	_, err := r.Write([]byte(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func openLog(t *testing.T, r *RotatingFile) *RotatingFile {
+	t.Helper()
+	err := r.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { r.Close() })
+	return r
+}
+
+func TestRotateSize(t *testing.T) {
+	clock := useFakeClock(t)
+	path := filepath.Join(t.TempDir(), "log")
+	r := openLog(t, &RotatingFile{Path: path, MaxSize: 10})
+
+	write(t, r, "12345678\n")
+	clock.advance(time.Second)
+	write(t, r, "abc\n")
+
+	rotated := backups(t, path)
+	if len(rotated) != 1 {
+		t.Fatalf("backups %v, want 1", rotated)
+	}
+	if got := read(t, rotated[0]); got != "12345678\n" {
+		t.Errorf("backup holds %q", got)
+	}
+	if got := read(t, path); got != "abc\n" {
+		t.Errorf("log holds %q", got)
+	}
+
+	// a write larger than MaxSize still goes to an empty file
+	clock.advance(time.Second)
+	write(t, r, "a line longer than ten bytes\n")
+	if got := read(t, path); got != "a line longer than ten bytes\n" {
+		t.Errorf("log holds %q", got)
+	}
+	if n := len(backups(t, path)); n != 2 {
+		t.Errorf("%d backups, want 2", n)
+	}
+}
+
+func TestRotateAge(t *testing.T) {
+	clock := useFakeClock(t)
+	path := filepath.Join(t.TempDir(), "log")
+	r := openLog(t, &RotatingFile{Path: path, RotateAfter: 24 * time.Hour})
+
+	write(t, r, "monday\n")
+	clock.advance(23 * time.Hour)
+	write(t, r, "still monday\n")
+	if n := len(backups(t, path)); n != 0 {
+		t.Fatalf("rotated after 23h, %d backups", n)
+	}
+
+	clock.advance(time.Hour)
+	write(t, r, "tuesday\n")
+	rotated := backups(t, path)
+	if len(rotated) != 1 || read(t, rotated[0]) != "monday\nstill monday\n" {
+		t.Fatalf("backups %v after a day", rotated)
+	}
+	if got := read(t, path); got != "tuesday\n" {
+		t.Errorf("log holds %q", got)
+	}
+	r.Close()
+
+	// the start of the file outlives the process writing it
+	clock.advance(23 * time.Hour)
+	r = openLog(t, &RotatingFile{Path: path, RotateAfter: 24 * time.Hour})
+	if n := len(backups(t, path)); n != 1 {
+		t.Errorf("reopening rotated too early, %d backups", n)
+	}
+	r.Close()
+
+	clock.advance(time.Hour)
+	openLog(t, &RotatingFile{Path: path, RotateAfter: 24 * time.Hour})
+	if n := len(backups(t, path)); n != 2 {
+		t.Errorf("reopening a day old log didn't rotate it, %d backups", n)
+	}
+}
+
+// a log written before the start was recorded is counted from the first open
+func TestRotateAgeUnknownStart(t *testing.T) {
+	clock := useFakeClock(t)
+	path := filepath.Join(t.TempDir(), "log")
+	err := ioutil.WriteFile(path, []byte("old\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := openLog(t, &RotatingFile{Path: path, RotateAfter: time.Hour})
+	write(t, r, "new\n")
+	if n := len(backups(t, path)); n != 0 {
+		t.Fatalf("%d backups, want the log kept", n)
+	}
+
+	clock.advance(time.Hour)
+	write(t, r, "later\n")
+	if n := len(backups(t, path)); n != 1 {
+		t.Errorf("%d backups an hour later, want 1", n)
+	}
+}
+
+func TestRotateMaxBackups(t *testing.T) {
+	clock := useFakeClock(t)
+	path := filepath.Join(t.TempDir(), "log")
+	r := openLog(t, &RotatingFile{Path: path, MaxSize: 5, MaxBackups: 2})
+
+	for _, line := range []string{"one\n", "two\n", "three\n", "four\n", "five\n"} {
+		write(t, r, line)
+		clock.advance(time.Second)
+	}
+
+	rotated := backups(t, path)
+	if len(rotated) != 2 {
+		t.Fatalf("backups %v, want the newest 2", rotated)
+	}
+	if read(t, rotated[0]) != "three\n" || read(t, rotated[1]) != "four\n" {
+		t.Errorf("kept %q and %q, want three and four", read(t, rotated[0]), read(t, rotated[1]))
+	}
+}
+
+func TestRotatePrunesOld(t *testing.T) {
+	clock := useFakeClock(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log")
+
+	old := path + "." + clock.now.Add(-31*24*time.Hour).Format(backupTimeFormat) + ".gz"
+	recent := path + "." + clock.now.Add(-29*24*time.Hour).Format(backupTimeFormat)
+	other := path + ".notes"
+	for _, file := range []string{old, recent, other} {
+		err := ioutil.WriteFile(file, []byte("x"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := openLog(t, &RotatingFile{Path: path, MaxSize: 5, MaxAge: 30 * 24 * time.Hour})
+	write(t, r, "one\n")
+	write(t, r, "two\n")
+
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Error("a backup older than MaxAge was kept")
+	}
+	for _, file := range []string{recent, other} {
+		if _, err := os.Stat(file); err != nil {
+			t.Errorf("%s was removed", filepath.Base(file))
+		}
+	}
+	if n := len(backups(t, path)); n != 2 {
+		t.Errorf("%d backups, want the recent one and the new one", n)
+	}
+}
+
+func TestRotateCompress(t *testing.T) {
+	useFakeClock(t)
+	path := filepath.Join(t.TempDir(), "log")
+	r := openLog(t, &RotatingFile{Path: path, MaxSize: 5, Compress: true})
+
+	write(t, r, "one\n")
+	write(t, r, "two\n")
+
+	rotated := backups(t, path)
+	if len(rotated) != 1 || !strings.HasSuffix(rotated[0], ".gz") {
+		t.Fatalf("backups %v, want one gzipped", rotated)
+	}
+	if _, err := os.Stat(strings.TrimSuffix(rotated[0], ".gz")); !os.IsNotExist(err) {
+		t.Error("the uncompressed backup was kept")
+	}
+
+	f, err := os.Open(rotated[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "one\n" {
+		t.Errorf("compressed backup holds %q", b)
+	}
+}
@@ -0,0 +1,240 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gladiusio/gladius-cli/config"
+	log "github.com/sirupsen/logrus"
+)
+
+// Session - the requests a command made and what the modules answered,
+// written by --record and served by --replay
+type Session struct {
+	CLIVersion string     `json:"cliVersion"`
+	Recorded   time.Time  `json:"recorded"`
+	Exchanges  []Exchange `json:"exchanges"`
+}
+
+// Exchange - a single attempt of a request. Secrets in the URL and the
+// bodies are redacted.
+type Exchange struct {
+	Module       string `json:"module,omitempty"` // name of the module the request was sent to, its host if it isn't one
+	Method       string `json:"method"`
+	URL          string `json:"url"`
+	RequestBody  string `json:"requestBody,omitempty"`
+	Status       int    `json:"status,omitempty"`
+	ContentType  string `json:"contentType,omitempty"`
+	ResponseBody string `json:"responseBody,omitempty"`
+	Error        string `json:"error,omitempty"` // the request failed, e.g. the module was offline
+}
+
+// ReplayMissError - the session has no response left for a request
+type ReplayMissError struct {
+	Module string
+	Method string
+	Path   string
+}
+
+func (e *ReplayMissError) Error() string {
+	return fmt.Sprintf("the replayed session has no response for %s %s of %s", e.Method, e.Path, e.Module)
+}
+
+// newRecordingTransport - writes every attempt of every request sent
+// through next to path, with the values of the given fields redacted
+func newRecordingTransport(next http.RoundTripper, path string, redactFields []string) *recordingTransport {
+	return &recordingTransport{
+		next:         next,
+		path:         path,
+		redactFields: redactFields,
+		session:      Session{CLIVersion: CLIVersion, Recorded: Now(), Exchanges: []Exchange{}},
+	}
+}
+
+// newReplayTransport - answers requests from the session at path, in the
+// order they were recorded. No request reaches the modules.
+func newReplayTransport(path string) (*replayTransport, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, HandleError(err, "Could not read the session to replay", "utils.newReplayTransport")
+	}
+
+	var session Session
+	err = json.Unmarshal(b, &session)
+	if err != nil {
+		return nil, HandleError(err, "Could not read the session to replay, it is not a recorded session", "utils.newReplayTransport")
+	}
+
+	return &replayTransport{exchanges: session.Exchanges, used: make([]bool, len(session.Exchanges))}, nil
+}
+
+// requestPath - what a replayed request is matched on with its module, the
+// host is left out as the ports of the modules differ between machines
+func requestPath(req *http.Request) string {
+	return req.URL.RequestURI()
+}
+
+// samePath - the request has the path of the recorded one, a value that was
+// redacted when recording matches any value
+func samePath(recorded, req *http.Request) bool {
+	if requestPath(recorded) == requestPath(req) {
+		return true
+	}
+	return redactedPattern(recorded.URL.Path, "[^/]*").MatchString(req.URL.Path) &&
+		redactedPattern(recorded.URL.RawQuery, "[^&]*").MatchString(req.URL.RawQuery)
+}
+
+// redactedPattern - matches the recorded text, with any match of value in
+// place of its redacted parts
+func redactedPattern(recorded, value string) *regexp.Regexp {
+	parts := strings.Split(recorded, "[REDACTED]")
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, value) + "$")
+}
+
+// requestModule - the module listening on the port a request is sent to,
+// from the ports in the config. The host when no module is on it.
+func requestModule(req *http.Request) string {
+	for _, module := range config.Modules {
+		if strconv.Itoa(config.GetInt(module.PortKey)) == req.URL.Port() {
+			return module.Name
+		}
+	}
+	return req.URL.Host
+}
+
+type recordingTransport struct {
+	next         http.RoundTripper
+	path         string
+	redactFields []string
+
+	mu      sync.Mutex
+	session Session
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		reqBody, _ = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	exchange := Exchange{
+		Module:      requestModule(req),
+		Method:      req.Method,
+		URL:         RedactURL(req.URL, t.redactFields),
+		RequestBody: string(RedactBody(reqBody, t.redactFields)),
+	}
+
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		exchange.Error = err.Error()
+		t.add(exchange)
+		return res, err
+	}
+
+	resBody, readErr := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+	if readErr != nil {
+		return res, readErr
+	}
+
+	exchange.Status = res.StatusCode
+	exchange.ContentType = res.Header.Get("Content-Type")
+	exchange.ResponseBody = string(RedactBody(resBody, t.redactFields))
+	t.add(exchange)
+
+	return res, nil
+}
+
+// add - the session file is rewritten after every request so it survives
+// the CLI exiting on an error
+func (t *recordingTransport) add(exchange Exchange) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.session.Exchanges = append(t.session.Exchanges, exchange)
+
+	b, err := json.MarshalIndent(t.session, "", "  ")
+	if err == nil {
+		err = ioutil.WriteFile(t.path, b, 0600)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"file": "session.go", "func": "add"}).Warning("Could not write the session file: ", err)
+	}
+}
+
+type replayTransport struct {
+	mu        sync.Mutex
+	exchanges []Exchange
+	used      []bool
+}
+
+// RoundTrip - the first unused exchange with the same module, method and
+// path. Modules answer in parallel, so their exchanges are interleaved
+// differently every run. Sessions recorded without the module only match on
+// the method and path.
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	module := requestModule(req)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, exchange := range t.exchanges {
+		if t.used[i] || !strings.EqualFold(exchange.Method, req.Method) {
+			continue
+		}
+		if exchange.Module != "" && exchange.Module != module {
+			continue
+		}
+		recorded, err := http.NewRequest(exchange.Method, exchange.URL, nil)
+		if err != nil || !samePath(recorded, req) {
+			continue
+		}
+		t.used[i] = true
+
+		log.WithFields(log.Fields{"file": "session.go", "func": "RoundTrip", "method": req.Method, "url": exchange.URL}).Debug("Replaying recorded response")
+
+		if exchange.Error != "" {
+			// replayed as a refused connection so it is handled like the
+			// original failure
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New(exchange.Error)}
+		}
+
+		header := make(http.Header)
+		if exchange.ContentType != "" {
+			header.Set("Content-Type", exchange.ContentType)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", exchange.Status, http.StatusText(exchange.Status)),
+			StatusCode:    exchange.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(strings.NewReader(exchange.ResponseBody)),
+			ContentLength: int64(len(exchange.ResponseBody)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, &ReplayMissError{Module: module, Method: req.Method, Path: requestPath(req)}
+}
@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// roundTripFunc - a transport answering with a function
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+var modulePorts = map[string]int{"Ports.Guardian": 7791, "Ports.EdgeD": 8081, "Ports.NetworkGateway": 3001}
+
+func setPorts(t *testing.T) {
+	for key, port := range modulePorts {
+		viper.Set(key, port)
+	}
+	t.Cleanup(func() {
+		for key := range modulePorts {
+			viper.Set(key, nil)
+		}
+	})
+}
+
+// versionOf - the /version answer of the module on a port
+func versionOf(req *http.Request) (*http.Response, error) {
+	body := fmt.Sprintf(`{"version": "%s.0.0"}`, req.URL.Port())
+	return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+}
+
+func get(t *testing.T, rt http.RoundTripper, url string) string {
+	t.Helper()
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadAll(res.Body)
+	return string(b)
+}
+
+// the modules are probed in parallel, the replay mustn't depend on the order
+// the session was recorded in
+func TestReplayMatchesModule(t *testing.T) {
+	setPorts(t)
+	path := filepath.Join(t.TempDir(), "session.json")
+
+	recorder := newRecordingTransport(roundTripFunc(versionOf), path, nil)
+	for _, port := range []int{7791, 8081, 3001} {
+		get(t, recorder, fmt.Sprintf("http://localhost:%d/version", port))
+	}
+	for i, module := range []string{"guardian", "edged", "network-gateway"} {
+		if got := recorder.session.Exchanges[i].Module; got != module {
+			t.Errorf("exchange %d recorded for %q, want %q", i, got, module)
+		}
+	}
+
+	replayer, err := newReplayTransport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, port := range []int{3001, 7791, 8081} {
+		want := fmt.Sprintf(`{"version": "%d.0.0"}`, port)
+		if got := get(t, replayer, fmt.Sprintf("http://localhost:%d/version", port)); got != want {
+			t.Errorf("port %d replayed %s, want %s", port, got, want)
+		}
+	}
+
+	req, _ := http.NewRequest("GET", "http://localhost:3001/version", nil)
+	_, err = replayer.RoundTrip(req)
+	if _, ok := err.(*ReplayMissError); !ok {
+		t.Errorf("replaying an exchange twice gave %v, want a ReplayMissError", err)
+	}
+}
+
+// sessions recorded before the module was saved match on the path only
+func TestReplayWithoutModule(t *testing.T) {
+	setPorts(t)
+	path := filepath.Join(t.TempDir(), "session.json")
+	session := `{"exchanges": [{"method": "GET", "url": "http://localhost:1/version", "status": 200, "responseBody": "old"}]}`
+	err := ioutil.WriteFile(path, []byte(session), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replayer, err := newReplayTransport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := get(t, replayer, "http://localhost:8081/version"); got != "old" {
+		t.Errorf("replayed %q, want the recorded answer", got)
+	}
+}
+
+// a value redacted when recording matches any value when replaying
+func TestReplayRedactedURL(t *testing.T) {
+	setPorts(t)
+	path := filepath.Join(t.TempDir(), "session.json")
+
+	answer := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader("found"))}, nil
+	})
+	recorder := newRecordingTransport(answer, path, []string{"email", "token"})
+	get(t, recorder, "http://localhost:3001/applications/jane@example.com?token=s3cret&page=2")
+
+	recorded := recorder.session.Exchanges[0].URL
+	if strings.Contains(recorded, "jane@example.com") || strings.Contains(recorded, "s3cret") {
+		t.Fatalf("recorded %s", recorded)
+	}
+
+	replayer, err := newReplayTransport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest("GET", "http://localhost:3001/applications/jane@example.com?token=s3cret&page=3", nil)
+	if _, err := replayer.RoundTrip(req); err == nil {
+		t.Error("a request with another page was replayed")
+	}
+	if got := get(t, replayer, "http://localhost:3001/applications/john@example.org?token=other&page=2"); got != "found" {
+		t.Errorf("replayed %q, want the recorded answer", got)
+	}
+}
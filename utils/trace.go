@@ -0,0 +1,244 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// redactedHeaders - headers that are never traced
+var redactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// emailPattern - an email address anywhere in a URL or a body that isn't JSON
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)
+
+type tracingTransport struct {
+	next         http.RoundTripper
+	harPath      string
+	redactFields []string
+
+	mu      sync.Mutex
+	entries []harEntry
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		reqBody, _ = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	started := time.Now()
+	res, err := t.next.RoundTrip(req)
+	latency := time.Since(started)
+
+	fields := log.Fields{
+		"method":          req.Method,
+		"url":             RedactURL(req.URL, t.redactFields),
+		"latency":         latency.String(),
+		"request_headers": t.headers(req.Header),
+		"request_body":    string(RedactBody(reqBody, t.redactFields)),
+	}
+
+	if err != nil {
+		log.WithFields(fields).Info("trace: request failed: ", err)
+		t.record(req, reqBody, nil, nil, started, latency)
+		return res, err
+	}
+
+	resBody, readErr := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+	if readErr != nil {
+		return res, readErr
+	}
+
+	fields["status"] = res.StatusCode
+	fields["response_headers"] = t.headers(res.Header)
+	fields["response_body"] = string(RedactBody(resBody, t.redactFields))
+	log.WithFields(fields).Info("trace")
+
+	t.record(req, reqBody, res, resBody, started, latency)
+
+	return res, nil
+}
+
+func (t *tracingTransport) headers(h http.Header) map[string]string {
+	out := make(map[string]string)
+	for name, values := range h {
+		value := strings.Join(values, ", ")
+		if isRedactedHeader(name, t.redactFields) {
+			value = "[REDACTED]"
+		}
+		out[name] = value
+	}
+	return out
+}
+
+// isRedactedHeader - the header carries credentials or is named after one of
+// the fields, X-Private-Key for privateKey or private_key
+func isRedactedHeader(name string, fields []string) bool {
+	for _, secret := range redactedHeaders {
+		if strings.EqualFold(name, secret) {
+			return true
+		}
+	}
+
+	squash := strings.NewReplacer("-", "", "_", "")
+	name = squash.Replace(name)
+	for _, field := range fields {
+		field = squash.Replace(field)
+		if strings.EqualFold(name, field) || strings.EqualFold(name, "x"+field) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactURL - the URL with the values of the named query parameters
+// replaced. Email addresses in the path and query are replaced too when
+// "email" is one of the fields.
+func RedactURL(u *url.URL, fields []string) string {
+	if u == nil {
+		return ""
+	}
+	if len(fields) == 0 {
+		return u.String()
+	}
+
+	redacted := *u
+	if redactsEmails(fields) && emailPattern.MatchString(u.Path) {
+		redacted.Path = emailPattern.ReplaceAllString(u.Path, "[REDACTED]")
+		redacted.RawPath = ""
+	}
+	redacted.RawQuery = redactQuery(u.RawQuery, fields)
+	return redacted.String()
+}
+
+// redactQuery - a query string or form encoded body with the values of the
+// named fields replaced, the order and encoding of the others is kept
+func redactQuery(query string, fields []string) string {
+	if query == "" {
+		return query
+	}
+
+	pairs := strings.Split(query, "&")
+	for i, pair := range pairs {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name, err := url.QueryUnescape(parts[0])
+		if err != nil {
+			name = parts[0]
+		}
+		value, err := url.QueryUnescape(parts[1])
+		if err != nil {
+			value = parts[1]
+		}
+		if redactQueryValue(name, value, fields) != value {
+			pairs[i] = parts[0] + "=[REDACTED]"
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// redactQueryValue - the value of a query parameter, or [REDACTED] if the
+// parameter is one of the fields or the value holds an email address
+func redactQueryValue(name, value string, fields []string) string {
+	if isRedactedField(name, fields) || (redactsEmails(fields) && emailPattern.MatchString(value)) {
+		return "[REDACTED]"
+	}
+	return value
+}
+
+func redactsEmails(fields []string) bool {
+	return isRedactedField("email", fields)
+}
+
+// RedactBody - the body with its secrets replaced: the named fields of a
+// JSON document or a form, or the email addresses of any other text when
+// "email" is one of the fields
+func RedactBody(body []byte, fields []string) []byte {
+	if len(body) == 0 || len(fields) == 0 {
+		return body
+	}
+
+	if json.Valid(body) {
+		return RedactJSON(body, fields)
+	}
+
+	if isForm(body) {
+		return []byte(redactQuery(string(body), fields))
+	}
+
+	if redactsEmails(fields) {
+		return emailPattern.ReplaceAll(body, []byte("[REDACTED]"))
+	}
+	return body
+}
+
+// isForm - the body looks form encoded, name=value pairs without spaces
+func isForm(body []byte) bool {
+	text := string(body)
+	if !strings.Contains(text, "=") || strings.ContainsAny(text, " \t\r\n") {
+		return false
+	}
+	_, err := url.ParseQuery(text)
+	return err == nil
+}
+
+// RedactJSON - replace the values of the named fields, at any depth, of a
+// JSON document. Anything that is not JSON is returned unchanged.
+func RedactJSON(body []byte, fields []string) []byte {
+	if len(body) == 0 || len(fields) == 0 {
+		return body
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return body
+	}
+
+	redacted, err := json.Marshal(redactValue(doc, fields))
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+func redactValue(value interface{}, fields []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			if isRedactedField(key, fields) {
+				v[key] = "[REDACTED]"
+			} else {
+				v[key] = redactValue(nested, fields)
+			}
+		}
+	case []interface{}:
+		for i, nested := range v {
+			v[i] = redactValue(nested, fields)
+		}
+	}
+	return value
+}
+
+func isRedactedField(key string, fields []string) bool {
+	for _, field := range fields {
+		if strings.EqualFold(key, field) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+package utils
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var testRedactFields = []string{"passphrase", "privateKey", "private_key", "email"}
+
+func TestRedactJSON(t *testing.T) {
+	tests := []struct {
+		name, body, want string
+	}{
+		{"top level", `{"passphrase":"hunter2","name":"node"}`, `{"name":"node","passphrase":"[REDACTED]"}`},
+		{"any case", `{"PassPhrase":"hunter2"}`, `{"PassPhrase":"[REDACTED]"}`},
+		{"nested", `{"node":{"wallet":{"private_key":"0xabc","address":"0x1"}}}`, `{"node":{"wallet":{"address":"0x1","private_key":"[REDACTED]"}}}`},
+		{"whole value", `{"email":{"work":"a@b.com"}}`, `{"email":"[REDACTED]"}`},
+		{"in arrays", `{"applications":[{"email":"a@b.com","score":1},{"email":"c@d.com"}]}`, `{"applications":[{"email":"[REDACTED]","score":1},{"email":"[REDACTED]"}]}`},
+		{"array at the top", `[{"privateKey":"0xabc"},[{"passphrase":"x"}]]`, `[{"privateKey":"[REDACTED]"},[{"passphrase":"[REDACTED]"}]]`},
+		{"nothing to redact", `{"balance":"1.5"}`, `{"balance":"1.5"}`},
+		{"not json", `passphrase=hunter2`, `passphrase=hunter2`},
+		{"empty", ``, ``},
+	}
+
+	for _, test := range tests {
+		if got := string(RedactJSON([]byte(test.body), testRedactFields)); got != test.want {
+			t.Errorf("%s: RedactJSON(%s) = %s, want %s", test.name, test.body, got, test.want)
+		}
+	}
+
+	if got := string(RedactJSON([]byte(`{"passphrase":"x"}`), nil)); got != `{"passphrase":"x"}` {
+		t.Errorf("redacted without fields: %s", got)
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name, body, want string
+	}{
+		{"json", `{"email":"a@b.com"}`, `{"email":"[REDACTED]"}`},
+		{"form", `name=node&passphrase=hunter%32&email=a%40b.com`, `name=node&passphrase=[REDACTED]&email=[REDACTED]`},
+		{"email in a form value", `contact=a%40b.com&page=2`, `contact=[REDACTED]&page=2`},
+		{"text", "could not apply, a@b.com already applied\n", "could not apply, [REDACTED] already applied\n"},
+		{"several emails", "a.b+c@mail.example.co.uk, d@e.io", "[REDACTED], [REDACTED]"},
+		{"text without secrets", "not found\n", "not found\n"},
+	}
+
+	for _, test := range tests {
+		if got := string(RedactBody([]byte(test.body), testRedactFields)); got != test.want {
+			t.Errorf("%s: RedactBody(%q) = %q, want %q", test.name, test.body, got, test.want)
+		}
+	}
+
+	// emails are only looked for when they are redacted
+	if got := string(RedactBody([]byte("from a@b.com"), []string{"passphrase"})); got != "from a@b.com" {
+		t.Errorf("redacted %q without email in the fields", got)
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	tests := []struct {
+		url, want string
+	}{
+		{"http://localhost:3001/api/status", "http://localhost:3001/api/status"},
+		{"http://localhost:3001/api/keystore?passphrase=hunter2", "http://localhost:3001/api/keystore?passphrase=[REDACTED]"},
+		{"http://localhost:3001/api/keystore?PRIVATEKEY=0xabc&page=2", "http://localhost:3001/api/keystore?PRIVATEKEY=[REDACTED]&page=2"},
+		{"http://localhost:3001/api/applications?page=2&contact=a%40b.com", "http://localhost:3001/api/applications?page=2&contact=[REDACTED]"},
+		{"http://localhost:3001/api/applications/a@b.com/status", "http://localhost:3001/api/applications/%5BREDACTED%5D/status"},
+		{"http://localhost:3001/api/applications?flag", "http://localhost:3001/api/applications?flag"},
+	}
+
+	for _, test := range tests {
+		u, err := url.Parse(test.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := RedactURL(u, testRedactFields); got != test.want {
+			t.Errorf("RedactURL(%s) = %s, want %s", test.url, got, test.want)
+		}
+		if u.String() != test.url {
+			t.Errorf("RedactURL changed the URL to %s", u)
+		}
+	}
+}
+
+func TestRedactedHeaders(t *testing.T) {
+	tests := []struct {
+		header   string
+		redacted bool
+	}{
+		{"Authorization", true},
+		{"cookie", true},
+		{"Set-Cookie", true},
+		{"Passphrase", true},
+		{"X-Passphrase", true},
+		{"X-Private-Key", true},
+		{"Private_Key", true},
+		{"X-Email", true},
+		{"Content-Type", false},
+		{"X-Request-Id", false},
+		{"X", false},
+	}
+
+	for _, test := range tests {
+		if got := isRedactedHeader(test.header, testRedactFields); got != test.redacted {
+			t.Errorf("isRedactedHeader(%s) = %v, want %v", test.header, got, test.redacted)
+		}
+	}
+}
+
+func TestTraceHAR(t *testing.T) {
+	harPath := filepath.Join(t.TempDir(), "trace.har")
+	module := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		header := http.Header{"Content-Type": {"application/json"}, "Set-Cookie": {"session=abc"}}
+		body := `{"applications":[{"email":"jane@example.com","approved":true}]}`
+		return &http.Response{StatusCode: http.StatusOK, Proto: "HTTP/1.1", Header: header, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+	})
+	tracer := &tracingTransport{next: module, harPath: harPath, redactFields: testRedactFields}
+
+	req, err := http.NewRequest("POST", "http://localhost:3001/api/apply?email=jane%40example.com&pool=home", strings.NewReader(`{"passphrase":"hunter2","pool":"home"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Private-Key", "0xabc")
+	req.Header.Set("Authorization", "Bearer token")
+	res, err := tracer.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the caller still gets the secrets
+	b, _ := ioutil.ReadAll(res.Body)
+	if !strings.Contains(string(b), "jane@example.com") {
+		t.Errorf("the response was redacted for the caller: %s", b)
+	}
+
+	b, err = ioutil.ReadFile(harPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"jane", "hunter2", "0xabc", "Bearer", "session=abc"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("the HAR file holds %q:\n%s", secret, b)
+		}
+	}
+
+	var har harFile
+	err = json.Unmarshal(b, &har)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(har.Log.Entries) != 1 {
+		t.Fatalf("%d entries, want 1", len(har.Log.Entries))
+	}
+	entry := har.Log.Entries[0]
+
+	if want := "http://localhost:3001/api/apply?email=[REDACTED]&pool=home"; entry.Request.URL != want {
+		t.Errorf("url %s, want %s", entry.Request.URL, want)
+	}
+	query := map[string]string{}
+	for _, q := range entry.Request.QueryString {
+		query[q.Name] = q.Value
+	}
+	if query["email"] != "[REDACTED]" || query["pool"] != "home" {
+		t.Errorf("query string %v", query)
+	}
+	headers := map[string]string{}
+	for _, h := range entry.Request.Headers {
+		headers[h.Name] = h.Value
+	}
+	if headers["X-Private-Key"] != "[REDACTED]" || headers["Authorization"] != "[REDACTED]" || headers["Content-Type"] != "application/json" {
+		t.Errorf("request headers %v", headers)
+	}
+	if entry.Request.PostData == nil || entry.Request.PostData.Text != `{"passphrase":"[REDACTED]","pool":"home"}` {
+		t.Errorf("post data %+v", entry.Request.PostData)
+	}
+	if want := `{"applications":[{"approved":true,"email":"[REDACTED]"}]}`; entry.Response.Content.Text != want {
+		t.Errorf("response %s, want %s", entry.Response.Content.Text, want)
+	}
+	if entry.Response.Status != http.StatusOK || entry.Response.Content.MimeType != "application/json" {
+		t.Errorf("response %+v", entry.Response)
+	}
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// FormatUnits - amount in the smallest unit of a token (e.g. wei) as a
+// decimal number of whole tokens, without trailing zeros
+func FormatUnits(amount *big.Int, decimals int) string {
+	if amount == nil {
+		return "0"
+	}
+
+	sign := ""
+	if amount.Sign() < 0 {
+		sign = "-"
+	}
+
+	digits := new(big.Int).Abs(amount).String()
+	if decimals <= 0 {
+		return sign + digits
+	}
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+
+	whole := digits[:len(digits)-decimals]
+	fraction := strings.TrimRight(digits[len(digits)-decimals:], "0")
+	if fraction == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + fraction
+}
+
+// ParseUnits - decimal number of whole tokens in the smallest unit of the
+// token, e.g. "1.5" with 18 decimals is 1500000000000000000 wei
+func ParseUnits(amount string, decimals int) (*big.Int, error) {
+	amount = strings.TrimSpace(amount)
+	parts := strings.Split(amount, ".")
+	if amount == "" || len(parts) > 2 || (parts[0] == "" && (len(parts) == 1 || parts[1] == "")) {
+		return nil, errors.New("invalid amount " + amount)
+	}
+
+	whole, fraction := parts[0], ""
+	if len(parts) == 2 {
+		fraction = strings.TrimRight(parts[1], "0")
+	}
+	if len(fraction) > decimals {
+		return nil, errors.New("amount " + amount + " has more decimals than the token")
+	}
+
+	digits := whole + fraction + strings.Repeat("0", decimals-len(fraction))
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return nil, errors.New("invalid amount " + amount)
+		}
+	}
+
+	value, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, errors.New("invalid amount " + amount)
+	}
+	return value, nil
+}
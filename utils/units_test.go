@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func bigInt(t *testing.T, s string) *big.Int {
+	t.Helper()
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		t.Fatalf("bad test number %s", s)
+	}
+	return n
+}
+
+func TestParseUnits(t *testing.T) {
+	tests := []struct {
+		amount   string
+		decimals int
+		want     string // in the smallest unit
+	}{
+		{"1", 18, "1000000000000000000"},
+		{"1.5", 18, "1500000000000000000"},
+		{"0.000000000000000001", 18, "1"},
+		{".5", 18, "500000000000000000"},
+		{"2.", 18, "2000000000000000000"},
+		{"  3.25\n", 18, "3250000000000000000"},
+		{"0", 18, "0"},
+		{"000.100", 18, "100000000000000000"},
+		{"1.10000000000000000000000", 18, "1100000000000000000"},
+		{"123456789012345678901234567890", 18, "123456789012345678901234567890000000000000000000"},
+		{"42", 0, "42"},
+		{"42.000", 0, "42"},
+		{"1.25", 2, "125"},
+	}
+
+	for _, test := range tests {
+		got, err := ParseUnits(test.amount, test.decimals)
+		if err != nil || got.String() != test.want {
+			t.Errorf("ParseUnits(%q, %d) = %v, %v, want %s", test.amount, test.decimals, got, err, test.want)
+		}
+	}
+}
+
+func TestParseUnitsInvalid(t *testing.T) {
+	tests := []struct {
+		amount   string
+		decimals int
+		err      string
+	}{
+		{"0.0000000000000000001", 18, "more decimals than the token"},
+		{"1.5", 0, "more decimals than the token"},
+		{"1.255", 2, "more decimals than the token"},
+		{"-1", 18, "invalid amount"},
+		{"-0.5", 18, "invalid amount"},
+		{"+1", 18, "invalid amount"},
+		{"1e18", 18, "invalid amount"},
+		{"1E-3", 18, "invalid amount"},
+		{"0x10", 18, "invalid amount"},
+		{"1,5", 18, "invalid amount"},
+		{"1 000", 18, "invalid amount"},
+		{"1.2.3", 18, "invalid amount"},
+		{"ten", 18, "invalid amount"},
+		{"½", 18, "invalid amount"},
+		{"١", 18, "invalid amount"},
+		{".", 18, "invalid amount"},
+		{"", 18, "invalid amount"},
+		{"   ", 18, "invalid amount"},
+	}
+
+	for _, test := range tests {
+		got, err := ParseUnits(test.amount, test.decimals)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("ParseUnits(%q, %d) = %v, %v, want %q", test.amount, test.decimals, got, err, test.err)
+		}
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	tests := []struct {
+		amount   string
+		decimals int
+		want     string
+	}{
+		{"1000000000000000000", 18, "1"},
+		{"1500000000000000000", 18, "1.5"},
+		{"1", 18, "0.000000000000000001"},
+		{"0", 18, "0"},
+		{"-1500000000000000000", 18, "-1.5"},
+		{"-1", 18, "-0.000000000000000001"},
+		{"123456789012345678901234567890", 18, "123456789012.34567890123456789"},
+		{"42", 0, "42"},
+		{"-42", 0, "-42"},
+		{"125", 2, "1.25"},
+		{"100", 2, "1"},
+	}
+
+	for _, test := range tests {
+		if got := FormatUnits(bigInt(t, test.amount), test.decimals); got != test.want {
+			t.Errorf("FormatUnits(%s, %d) = %s, want %s", test.amount, test.decimals, got, test.want)
+		}
+	}
+
+	if got := FormatUnits(nil, 18); got != "0" {
+		t.Errorf("FormatUnits(nil) = %s", got)
+	}
+}
+
+func TestUnitsRoundTrip(t *testing.T) {
+	maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	amounts := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(10),
+		bigInt(t, "999999999999999999"),
+		bigInt(t, "1000000000000000000"),
+		bigInt(t, "1000000000000000001"),
+		bigInt(t, "31415926535897932384626"),
+		maxUint256,
+	}
+
+	for _, amount := range amounts {
+		formatted := FormatUnits(amount, 18)
+		parsed, err := ParseUnits(formatted, 18)
+		if err != nil || parsed.Cmp(amount) != 0 {
+			t.Errorf("%s formatted as %s parsed back as %v, %v", amount, formatted, parsed, err)
+		}
+	}
+
+	for _, amount := range []string{"1", "0.1", "1.000000000000000001", "250000", "0.000000000000000001"} {
+		parsed, err := ParseUnits(amount, 18)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if formatted := FormatUnits(parsed, 18); formatted != amount {
+			t.Errorf("%s parsed and formatted as %s", amount, formatted)
+		}
+	}
+}
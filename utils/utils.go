@@ -2,17 +2,22 @@ package utils
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"math/big"
+	"net"
 	"net/http"
+	neturl "net/url"
 	"strings"
 	"time"
 
+	"github.com/gladiusio/gladius-cli/config"
 	"github.com/mgutz/ansi"
 	log "github.com/sirupsen/logrus"
 	survey "gopkg.in/AlecAivazis/survey.v1"
-	"gopkg.in/AlecAivazis/survey.v1/terminal"
 )
 
 // APIResponse - standard response from the control daemon api
@@ -35,9 +40,6 @@ type ErrorResponse struct {
 var cachedPassphrase string
 var attempts = 0
 
-// RequestTimeout - Request timeout in seconds
-var RequestTimeout int
-
 // Error - for the dev/logger
 func (e *ErrorResponse) Error() string {
 	return e.LogError
@@ -48,16 +50,87 @@ func (e *ErrorResponse) Message() string {
 	return e.UserMessage
 }
 
-// For control over HTTP client headers,
-// redirect policy, and other settings,
-// create an HTTP client
-var client = &http.Client{
-	Timeout: time.Second * time.Duration(RequestTimeout),
+// ClientOptions - how the requests of a client are sent
+type ClientOptions struct {
+	RequestTimeout        time.Duration // for the whole request
+	ConnectTimeout        time.Duration // for connecting to a module
+	ResponseHeaderTimeout time.Duration // for a module to start answering
+	Retry                 RetryPolicy
+	Trace                 bool     // log every request
+	TraceHAR              string   // also write the traced requests to this HAR file
+	RecordPath            string   // write every request and response to this session file
+	ReplayPath            string   // answer requests from this session file instead of the modules
+	RedactFields          []string // fields of bodies, query strings and headers whose values are never logged, traced nor recorded
+}
+
+// DefaultClientOptions - used for requests whose context carries no client
+var DefaultClientOptions = ClientOptions{
+	RequestTimeout:        10 * time.Second,
+	ConnectTimeout:        3 * time.Second,
+	ResponseHeaderTimeout: 10 * time.Second,
+	Retry:                 DefaultRetry,
+}
+
+// defaultClient - sends the requests whose context carries no client
+var defaultClient, _ = NewClient(DefaultClientOptions)
+
+type clientKey struct{}
+
+// WithClient - ctx whose requests SendRequest sends through d, so every
+// command tree has its own client
+func WithClient(ctx context.Context, d Doer) context.Context {
+	return context.WithValue(ctx, clientKey{}, d)
+}
+
+// clientFrom - the client set by WithClient, the default one otherwise
+func clientFrom(ctx context.Context) Doer {
+	if d, ok := ctx.Value(clientKey{}).(Doer); ok {
+		return d
+	}
+	return defaultClient
+}
+
+// NewClient - every attempt goes through the recorder and the tracer (if
+// any), the retry policy wraps them all. A replayed session takes the place
+// of the modules.
+func NewClient(opts ClientOptions) (Doer, error) {
+	dialer := &net.Dialer{
+		Timeout:   opts.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	var transport http.RoundTripper = &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   opts.ConnectTimeout,
+		ResponseHeaderTimeout: opts.ResponseHeaderTimeout,
+	}
+
+	if opts.ReplayPath != "" {
+		replayer, err := newReplayTransport(opts.ReplayPath)
+		if err != nil {
+			return nil, err
+		}
+		transport = replayer
+	}
+
+	if opts.RecordPath != "" {
+		transport = newRecordingTransport(transport, opts.RecordPath, opts.RedactFields)
+	}
+
+	if opts.Trace || opts.TraceHAR != "" {
+		transport = &tracingTransport{next: transport, harPath: opts.TraceHAR, redactFields: opts.RedactFields}
+	}
+
+	return &http.Client{
+		Timeout:   opts.RequestTimeout,
+		Transport: &retryTransport{next: transport, policy: opts.Retry, breakers: newBreakerSet(), redactFields: opts.RedactFields},
+	}, nil
 }
 
 // SendRequest - custom function to make sending api requests less of a pain
-// in the arse.
-func SendRequest(requestType, url string, data interface{}) (string, error) {
+// in the arse. The request is abandoned when ctx is cancelled.
+func SendRequest(ctx context.Context, requestType, url string, data interface{}) (string, error) {
 	b := bytes.Buffer{}
 
 	// if data present, turn it into a bytesBuffer(jsonPayload)
@@ -74,138 +147,209 @@ func SendRequest(requestType, url string, data interface{}) (string, error) {
 	if err != nil {
 		return "", HandleError(err, "Could not build request", ":http.NewRequest/SendRequest")
 	}
+	req = req.WithContext(ctx)
 
 	req.Header.Set("User-Agent", "gladius-cli")
 	req.Header.Set("Content-Type", "application/json")
 
 	// Send the request via a client
-	res, err := client.Do(req)
+	res, err := clientFrom(ctx).Do(req)
 	if err != nil {
+		if ctx.Err() == context.Canceled {
+			return "", HandleError(err, "Cancelled", ":client.Do/SendRequest")
+		}
+		if urlErr, ok := err.(*neturl.Error); ok {
+			if circuitErr, ok := urlErr.Err.(*CircuitOpenError); ok {
+				return "", HandleError(err, circuitErr.Error(), ":client.Do/SendRequest")
+			}
+			if missErr, ok := urlErr.Err.(*ReplayMissError); ok {
+				return "", HandleError(err, missErr.Error(), ":client.Do/SendRequest")
+			}
+		}
 		return "", HandleError(err, "Could not send request", ":client.Do/SendRequest")
 	}
 
+	// Defer the closing of the body
+	defer res.Body.Close()
+
 	switch res.StatusCode {
 	case 403:
 		fallthrough
 	case 405:
-		terminal.Print(ansi.Color("[ERROR] ", "196+hb"))
-		terminal.Println(ansi.Color("Could not unlock wallet, please try again", "255+hb"))
+		fmt.Fprint(Stdout, ansi.Color("[ERROR] ", "196+hb"))
+		fmt.Fprintln(Stdout, ansi.Color("Could not unlock wallet, please try again", "255+hb"))
 		if attempts < 3 {
 			attempts++
-			_, err := OpenAccount()
+			_, err := OpenAccount(ctx)
 			if err != nil {
 				return "", HandleError(err, "", "utils.StatusCodeHandler")
 			}
-			return SendRequest(requestType, url, data)
+			return SendRequest(ctx, requestType, url, data)
 		}
 	}
 
 	// read the body of the response
 	body, readErr := ioutil.ReadAll(res.Body)
 	if readErr != nil {
-		return "", HandleError(err, "Could not build request", ":ioutil.ReadAll/SendRequest")
+		return "", HandleError(readErr, "Could not read response", ":ioutil.ReadAll/SendRequest")
 	}
 
-	// Defer the closing of the body
-	defer res.Body.Close()
-
 	return string(body), nil //tx
 }
 
-// CheckTx - check status of tx.
+// TxStatus - where a transaction is on the blockchain
+type TxStatus struct {
+	Hash          string `json:"hash"`
+	Complete      bool   `json:"complete"`      // mined into a block
+	Failed        bool   `json:"failed"`        // mined but reverted
+	BlockNumber   int64  `json:"blockNumber"`   // 0 while pending
+	Confirmations int64  `json:"confirmations"` // blocks since it was mined, counting its own
+}
+
+// TxHashString - hash of the transaction a request submitted, "" if it didn't
+func (r APIResponse) TxHashString() string {
+	switch hash := r.TxHash.(type) {
+	case string:
+		return hash
+	case map[string]interface{}:
+		for _, key := range []string{"value", "hash", "txHash"} {
+			if value, ok := hash[key].(string); ok {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// GetTx - status of tx.
 // Perform a single check on a tx.
-// DEPRECATED
-func CheckTx(tx string) (bool, error) {
-	url := fmt.Sprintf("http://localhost:3001/api/status/tx/%s", tx)
+func GetTx(ctx context.Context, tx string) (TxStatus, error) {
+	url := fmt.Sprintf("http://localhost:%d/api/status/tx/%s", config.GetInt("Ports.NetworkGateway"), tx)
 
-	res, err := SendRequest("GET", url, nil)
+	res, err := SendRequest(ctx, "GET", url, nil)
 	if err != nil {
-		return false, HandleError(err, "", "utils.CheckTx")
+		return TxStatus{}, HandleError(err, "", "utils.GetTx")
 	}
 
 	api, err := ControlDaemonHandler([]byte(res))
 	if err != nil {
-		return false, HandleError(err, "", "utils.CheckTx")
+		return TxStatus{}, HandleError(err, "", "utils.GetTx")
 	}
 
-	response := api.Response.(map[string]interface{})
+	response, ok := api.Response.(map[string]interface{})
+	if !ok {
+		return TxStatus{}, HandleError(errors.New("no tx status in response"), "Invalid server response", "utils.GetTx")
+	}
 
-	if response["complete"] == false {
-		return false, nil
+	status := TxStatus{Hash: tx}
+	status.Complete, _ = response["complete"].(bool)
+	if success, ok := response["success"].(bool); ok {
+		status.Failed = status.Complete && !success
+	}
+	if block, ok := response["blockNumber"].(float64); ok {
+		status.BlockNumber = int64(block)
+	}
+	if confirmations, ok := response["confirmations"].(float64); ok {
+		status.Confirmations = int64(confirmations)
+	} else if current, ok := response["currentBlock"].(float64); ok && status.BlockNumber > 0 {
+		status.Confirmations = int64(current) - status.BlockNumber + 1
+	}
+	if status.Complete && status.Confirmations < 1 {
+		status.Confirmations = 1
 	}
 
-	return response["complete"].(bool), nil // tx completion status
+	return status, nil // tx completion status
 }
 
-// WaitForTx - wait for a tx on the blockchain to complete.
-// Queries the API every second to see if tx is complete.
-// DEPRECATED
-func WaitForTx(tx string) (bool, error) {
-	ticker := time.NewTicker(1 * time.Second)
-	quit := make(chan error) // this is the exit condition channel
-
-	println()
-
-	// hit the status API every 1 second
-	go func() {
-		count := 0
-		for {
-			select {
-			case <-ticker.C:
-				status, err := CheckTx(tx)
-				if err != nil {
-					quit <- err // if there's an error here then pump it into the channel
-				}
-				if status {
-					quit <- nil // if the tx went through, pump a nil error into the channel
-				}
-				switch count {
-				case 0:
-					fmt.Printf("Tx: %s\t Status: Pending   \r", tx)
-				case 1:
-					fmt.Printf("Tx: %s\t Status: Pending.  \r", tx)
-				case 2:
-					fmt.Printf("Tx: %s\t Status: Pending.. \r", tx)
-				case 3:
-					fmt.Printf("Tx: %s\t Status: Pending...\r", tx)
-				default:
-					count = -1
-				}
-				count++
+// CheckTx - check status of tx.
+// Perform a single check on a tx.
+func CheckTx(ctx context.Context, tx string) (bool, error) {
+	status, err := GetTx(ctx, tx)
+	if err != nil {
+		return false, HandleError(err, "", "utils.CheckTx")
+	}
+	return status.Complete, nil
+}
+
+// WaitForTx - wait for a tx on the blockchain to be mined and confirmed by
+// the given number of blocks. Queries the API every interval, calling
+// progress with each status, until then or until ctx is done.
+func WaitForTx(ctx context.Context, tx string, confirmations int64, interval time.Duration, progress func(TxStatus)) (TxStatus, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		status, err := GetTx(ctx, tx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return status, HandleError(ctx.Err(), "", "utils.WaitForTx")
 			}
+			return status, HandleError(err, "", "utils.WaitForTx")
+		}
+		if progress != nil {
+			progress(status)
+		}
+		if status.Failed || (status.Complete && status.Confirmations >= confirmations) {
+			return status, nil
 		}
-	}()
 
-	err := <-quit
-	if err != nil {
-		return false, HandleError(err, "", "utils.WaitForTx")
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return status, HandleError(ctx.Err(), "", "utils.WaitForTx")
+		}
 	}
-
-	fmt.Printf("\nTx: %s\t Status: Successful\n", tx)
-	return true, nil
 }
 
-// CheckBalance - check SYMBOL balance of account
-// DEPRECATED
-func CheckBalance(address, symbol string) (float64, error) {
-	url := fmt.Sprintf("http://localhost:3001/api/account/%s/balance/%s", address, symbol)
+// CheckBalance - check SYMBOL balance of account, in the smallest unit of
+// the token (wei for ETH)
+func CheckBalance(ctx context.Context, address, symbol string) (*big.Int, error) {
+	url := fmt.Sprintf("http://localhost:%d/api/account/%s/balance/%s", config.GetInt("Ports.NetworkGateway"), address, strings.ToLower(symbol))
 
-	res, err := SendRequest("GET", url, nil)
+	res, err := SendRequest(ctx, "GET", url, nil)
 	if err != nil {
-		return 0, HandleError(err, "", "utils.CheckBalance")
+		return nil, HandleError(err, "", "utils.CheckBalance")
 	}
 
-	api, err := ControlDaemonHandler([]byte(res))
+	_, err = ControlDaemonHandler([]byte(res))
 	if err != nil {
-		return 0, HandleError(err, "", "utils.CheckBalance")
+		return nil, HandleError(err, "", "utils.CheckBalance")
 	}
 
-	response := api.Response.(map[string]interface{})
-	balance := response["value"].(float64)
+	// decoded again as a float64 can't hold every balance in wei
+	var body struct {
+		Response struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"response"`
+	}
+	err = json.Unmarshal([]byte(res), &body)
+	if err != nil {
+		return nil, HandleError(err, "Invalid server response", "utils.CheckBalance")
+	}
+
+	balance, ok := ParseAmount(strings.Trim(string(body.Response.Value), `"`))
+	if !ok {
+		return nil, HandleError(fmt.Errorf("invalid balance %s", body.Response.Value), "Invalid server response", "utils.CheckBalance")
+	}
 
 	return balance, nil // value of $SYMBOL in account
 }
 
+// ParseAmount - integer amount as a decimal number, a 0x prefixed hex number
+// or in exponent notation
+func ParseAmount(s string) (*big.Int, bool) {
+	if strings.HasPrefix(s, "0x") {
+		return new(big.Int).SetString(s[2:], 16)
+	}
+
+	f, ok := new(big.Float).SetPrec(256).SetString(s)
+	if !ok || f.Sign() < 0 {
+		return nil, false
+	}
+	amount, _ := f.Int(nil)
+	return amount, true
+}
+
 // ControlDaemonHandler - handler for the API responses
 func ControlDaemonHandler(_res []byte) (APIResponse, error) {
 	var response = APIResponse{}
@@ -216,7 +360,7 @@ func ControlDaemonHandler(_res []byte) (APIResponse, error) {
 	}
 
 	if !response.Success {
-		return APIResponse{}, HandleError(fmt.Errorf(response.Error), response.Message, ":APIResponse/utils.ControlDaemonHandler")
+		return APIResponse{}, HandleError(errors.New(response.Error), response.Message, ":APIResponse/utils.ControlDaemonHandler")
 	}
 
 	return response, nil
@@ -238,14 +382,14 @@ func HandleError(err error, msg, path string) error {
 // Use this to println the UserMessage and log the LogError with correct path.
 func PrintError(err error) {
 	if err, ok := err.(*ErrorResponse); ok {
-		terminal.Print(ansi.Color("[ERROR] ", "196+hb"))
-		terminal.Println(ansi.Color(err.Message(), "255+hb"))
+		fmt.Fprint(Stdout, ansi.Color("[ERROR] ", "196+hb"))
+		fmt.Fprintln(Stdout, ansi.Color(err.Message(), "255+hb"))
 		log.WithFields(log.Fields{"path": err.Path}).Fatal(err.LogError)
 		return
 	}
 
-	terminal.Print(ansi.Color("[ERROR] ", "196+hb"))
-	terminal.Println(ansi.Color(fmt.Sprint(err), "255+hb"))
+	fmt.Fprint(Stdout, ansi.Color("[ERROR] ", "196+hb"))
+	fmt.Fprintln(Stdout, ansi.Color(fmt.Sprint(err), "255+hb"))
 	log.Fatal(fmt.Sprint(err))
 
 }
@@ -253,11 +397,11 @@ func PrintError(err error) {
 // GetIP - Retrieve the current machine's external IPv4 address
 // using multiple ip API's.
 // DEPRECATED
-func GetIP() (string, error) {
+func GetIP(ctx context.Context) (string, error) {
 	sites := [4]string{"https://ipv4.myexternalip.com/raw", "https://api.ipify.org/?format=text", "https://ident.me/", "https://ipv4bot.whatismyipaddress.com"}
 
 	for _, site := range sites {
-		res, err := SendRequest("GET", site, nil)
+		res, err := SendRequest(ctx, "GET", site, nil)
 		if err == nil {
 			return res, nil
 		}
@@ -271,16 +415,16 @@ func NewPassphrase() string {
 	prompt := &survey.Password{
 		Message: "Create a passphrase for your new wallet: ",
 	}
-	survey.AskOne(prompt, &password1, nil)
+	Prompt.AskOne(prompt, &password1, nil)
 
 	password2 := ""
 	prompt = &survey.Password{
 		Message: "Confirm your passphrase: ",
 	}
-	survey.AskOne(prompt, &password2, nil)
+	Prompt.AskOne(prompt, &password2, nil)
 
 	if strings.Compare(password1, password2) != 0 {
-		fmt.Println("Passwords do not match. Please try again")
+		fmt.Fprintln(Stdout, "Passwords do not match. Please try again")
 		return NewPassphrase()
 	}
 
@@ -293,7 +437,7 @@ func AskPassphrase() string {
 	prompt := &survey.Password{
 		Message: "Please type your passphrase: ",
 	}
-	survey.AskOne(prompt, &password, nil)
+	Prompt.AskOne(prompt, &password, nil)
 	return password
 }
 
@@ -303,28 +447,16 @@ func CachePassphrase(passphrase string) {
 	cachedPassphrase = passphrase
 }
 
-// Version - print version of each module
-func Version() {
-	res, err := SendRequest("GET", "localhost:8080/status", nil)
-	if err != nil {
-		PrintError(err)
-	}
-
-	terminal.Println(ansi.Color("CLI: ", "83+hb"), ansi.Color("0.4.0", "255+hb"))
-	terminal.Println(ansi.Color("Control Daemon: ", "83+hb"), ansi.Color("0.4.0", "255+hb"))
-	terminal.Println(ansi.Color("Network Daemon: ", "83+hb"), ansi.Color(res, "255+hb"))
-}
-
 // OpenAccount - open/unlock an account
-func OpenAccount() (bool, error) {
-	url := "http://localhost:3001/api/keystore/account/open"
+func OpenAccount(ctx context.Context) (bool, error) {
+	url := fmt.Sprintf("http://localhost:%d/api/keystore/account/open", config.GetInt("Ports.NetworkGateway"))
 
 	passphrase := AskPassphrase()
 	data := make(map[string]interface{})
 	data["passphrase"] = passphrase
 
 	log.WithFields(log.Fields{"file": "wallet.go", "func": "OpenAccount"}).Debug("POST: ", url)
-	res, err := SendRequest("POST", url, data)
+	res, err := SendRequest(ctx, "POST", url, data)
 	if err != nil {
 		return false, HandleError(err, "", "utils.OpenAccount")
 	}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Build metadata, set at build time with
+// -ldflags "-X github.com/gladiusio/gladius-cli/utils.CLIVersion=..."
+var (
+	// CLIVersion - version of this CLI
+	CLIVersion = "dev"
+	// GitCommit - commit the CLI was built from
+	GitCommit = "unknown"
+	// BuildDate - when the CLI was built (RFC 3339)
+	BuildDate = "unknown"
+)
+
+// GoVersion - version of Go the CLI was built with
+func GoVersion() string {
+	return runtime.Version()
+}
+
+// CompareVersions - compare two "major.minor.patch" versions, returning -1,
+// 0 or 1. A leading "v" and anything after a "-" or "+" is ignored, missing
+// parts count as 0.
+func CompareVersions(a, b string) (int, error) {
+	pa, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	pb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range pa {
+		if pa[i] < pb[i] {
+			return -1, nil
+		}
+		if pa[i] > pb[i] {
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func parseVersion(version string) ([3]int, error) {
+	var parts [3]int
+
+	v := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+
+	fields := strings.Split(v, ".")
+	if v == "" || len(fields) > 3 {
+		return parts, fmt.Errorf("invalid version %q", version)
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return parts, fmt.Errorf("invalid version %q", version)
+		}
+		parts[i] = n
+	}
+
+	return parts, nil
+}
@@ -0,0 +1,55 @@
+package utils
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		cmp  int
+	}{
+		{"0.7.0", "0.7.0", 0},
+		{"0.7.0", "0.8.0", -1},
+		{"0.8.0", "0.7.9", 1},
+		{"1.0.0", "0.99.99", 1},
+		{"0.10.0", "0.9.0", 1},
+		{"0.7.10", "0.7.9", 1},
+		{"2.0.0", "10.0.0", -1},
+		// a leading v and spaces
+		{"v0.7.0", "0.7.0", 0},
+		{" 0.8.0\n", "v0.7.0", 1},
+		// missing parts count as 0
+		{"0.8", "0.8.0", 0},
+		{"0.8", "0.8.1", -1},
+		{"1", "0.9.9", 1},
+		{"1", "1.0.0", 0},
+		// pre-release and build suffixes are ignored
+		{"0.8.0-rc1", "0.8.0", 0},
+		{"0.8.0-beta.2", "0.8.0-alpha", 0},
+		{"0.8.0+build5", "0.8.0", 0},
+		{"0.8.0-rc1", "0.7.9", 1},
+		{"0.8-rc1", "0.8.1", -1},
+	}
+
+	for _, test := range tests {
+		cmp, err := CompareVersions(test.a, test.b)
+		if err != nil || cmp != test.cmp {
+			t.Errorf("CompareVersions(%q, %q) = %d, %v, want %d", test.a, test.b, cmp, err, test.cmp)
+		}
+		// the other way round
+		cmp, err = CompareVersions(test.b, test.a)
+		if err != nil || cmp != -test.cmp {
+			t.Errorf("CompareVersions(%q, %q) = %d, %v, want %d", test.b, test.a, cmp, err, -test.cmp)
+		}
+	}
+}
+
+func TestCompareVersionsInvalid(t *testing.T) {
+	for _, version := range []string{"", " ", "v", "dev", "latest", "1.2.3.4", "1..2", "1.2.", ".1", "1.x.0", "-1.0.0", "1.-2.0", "0x1.0.0", "-rc1", "1.2.3 beta"} {
+		if cmp, err := CompareVersions(version, "0.7.0"); err == nil {
+			t.Errorf("CompareVersions(%q, 0.7.0) = %d, want an error", version, cmp)
+		}
+		if cmp, err := CompareVersions("0.7.0", version); err == nil {
+			t.Errorf("CompareVersions(0.7.0, %q) = %d, want an error", version, cmp)
+		}
+	}
+}